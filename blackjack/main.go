@@ -2,186 +2,177 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
 	"github.com/jeremy-miller/gophercises/deck"
 )
 
-type Hand []deck.Card
+// moveKeys is the key the player types for each move, and how the move is offered to them.
+var moveKeys = []struct {
+	move, key, label string
+}{
+	{"hit", "h", "(h)it"},
+	{"stand", "s", "(s)tand"},
+	{"double", "d", "(d)ouble"},
+	{"split", "p", "s(p)lit"},
+	{"surrender", "r", "su(r)render"},
+}
 
-func (h Hand) String() string {
-	strs := make([]string, len(h))
-	for i := range h {
-		strs[i] = h[i].String()
+func handString(cards []deck.Card) string {
+	strs := make([]string, len(cards))
+	for i := range cards {
+		strs[i] = cards[i].String()
 	}
 	return strings.Join(strs, ", ")
 }
 
-func (h Hand) DealerString() string {
-	return h[0].String() + ", **HIDDEN**"
+// player is the human at the table, who is shown the game through snapshots of its state.
+type player struct {
+	game      *blackjack.Game
+	outcomes  []blackjack.Outcome
+	insurance *int // the net of the insurance bet, if the player took it
 }
 
-func (h Hand) Score() int {
-	minScore := h.MinScore()
-	if minScore > 11 {
-		return minScore
+func (p *player) Bet(shuffled bool) int {
+	opts := p.game.Options()
+	limits := fmt.Sprintf("at least %d", opts.MinBet)
+	if opts.MaxBet > 0 {
+		limits = fmt.Sprintf("%d to %d", opts.MinBet, opts.MaxBet)
 	}
-	for _, c := range h {
-		if c.Rank == deck.Ace {
-			return minScore + 10 // ace is currently worth 1, so adding 10 to make it worth 11
+	var input string
+	for {
+		fmt.Printf("How much will you bet? (%s)\n", limits)
+		fmt.Scanf("%s\n", &input)
+		bet, err := strconv.Atoi(input)
+		if err == nil && bet >= opts.MinBet && (opts.MaxBet == 0 || bet <= opts.MaxBet) {
+			return bet
 		}
-		// only 1 ace can be used as 11 and still be under 21
-	}
-	return minScore
-}
-
-func (h Hand) MinScore() int {
-	score := 0
-	for _, c := range h {
-		score += min(int(c.Rank), 10)
+		fmt.Println("Invalid bet:", input)
 	}
-	return score
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+func (p *player) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	gs := p.game.State()
+	seat := gs.Seats[gs.Turn]
+	dealerString := handString(gs.Dealer)
+	if gs.HoleCard {
+		dealerString += ", **HIDDEN**"
 	}
-	return b
-}
-
-func main() {
-	var gs GameState
-	gs = Shuffle(gs)
-	gs = Deal(gs)
-	var input string
-	for gs.State == StatePlayerTurn {
-		fmt.Println("Player:", gs.Player)
-		fmt.Println("Dealer:", gs.Dealer.DealerString())
-		fmt.Println("What will you do? (h)it, (s)tand")
-		fmt.Scanf("%s\n", &input)
-		switch input {
-		case "h":
-			gs = Hit(gs)
-		case "s":
-			gs = Stand(gs)
-		default:
-			fmt.Println("Invalid option:", input)
+	var labels []string
+	legal := make(map[string]blackjack.Move)
+	for _, name := range p.game.LegalMoves() {
+		for _, mk := range moveKeys {
+			if mk.move == name {
+				labels = append(labels, mk.label)
+				legal[mk.key], _ = blackjack.MoveByName(name)
+			}
 		}
 	}
-	for gs.State == StateDealerTurn {
-		if gs.Dealer.Score() < 16 || (gs.Dealer.Score() == 17 && gs.Dealer.MinScore() != 17) { // dealer hits if < 16 or soft 17 (ace)
-			gs = Hit(gs)
+	var input string
+	for {
+		if len(seat.Hands) > 1 {
+			fmt.Printf("Player (hand %d of %d): %s\n", seat.Active+1, len(seat.Hands), handString(hand))
 		} else {
-			gs = Stand(gs)
+			fmt.Println("Player:", handString(hand))
+		}
+		fmt.Println("Dealer:", dealerString)
+		fmt.Printf("What will you do? %s\n", strings.Join(labels, ", "))
+		fmt.Scanf("%s\n", &input)
+		if move, ok := legal[input]; ok {
+			return move
 		}
+		fmt.Println("Invalid option:", input)
 	}
-	gs = EndHand(gs)
 }
 
-func Shuffle(gs GameState) GameState {
-	ret := clone(gs)
-	ret.Deck = deck.New(deck.Deck(3), deck.Shuffle)
-	return ret
-}
-
-func Deal(gs GameState) GameState {
-	ret := clone(gs)
-	ret.Player = make(Hand, 0, 5) // likely won't have more than 5 cards in hand in a game
-	ret.Dealer = make(Hand, 0, 5)
-	var card deck.Card
-	for i := 0; i < 2; i++ {
-		card, ret.Deck = draw(ret.Deck)
-		ret.Player = append(ret.Player, card)
-		card, ret.Deck = draw(ret.Deck)
-		ret.Dealer = append(ret.Dealer, card)
+func (p *player) Insurance(hand []deck.Card) bool {
+	var input string
+	for {
+		fmt.Println("Player:", handString(hand))
+		if blackjack.Blackjack(hand...) {
+			fmt.Println("The dealer shows an Ace. Take even money? (y)es, (n)o")
+		} else {
+			fmt.Println("The dealer shows an Ace. Take insurance? (y)es, (n)o")
+		}
+		fmt.Scanf("%s\n", &input)
+		switch input {
+		case "y":
+			return true
+		case "n":
+			return false
+		}
+		fmt.Println("Invalid option:", input)
 	}
-	ret.State = StatePlayerTurn
-	return ret
 }
 
-func draw(cards []deck.Card) (deck.Card, []deck.Card) {
-	return cards[0], cards[1:]
+func (p *player) Surrender(hand []deck.Card, dealer deck.Card) bool {
+	return false // the table doesn't offer early surrender
 }
 
-func Hit(gs GameState) GameState {
-	ret := clone(gs)
-	hand := ret.CurrentPlayer()
-	var card deck.Card
-	card, ret.Deck = draw(ret.Deck)
-	*hand = append(*hand, card)
-	if hand.Score() > 21 {
-		return Stand(ret)
+// ObserveEvent implements blackjack.Observer, keeping the outcome of each of the player's hands and of
+// their insurance bet.
+func (p *player) ObserveEvent(e blackjack.Event) {
+	switch e.Type {
+	case blackjack.EventBet:
+		p.outcomes = nil
+		p.insurance = nil
+	case blackjack.EventSettle:
+		if e.Outcome == blackjack.OutcomeInsurance {
+			amount := e.Amount
+			p.insurance = &amount
+		} else {
+			p.outcomes = append(p.outcomes, e.Outcome)
+		}
 	}
-	return ret
 }
 
-func Stand(gs GameState) GameState {
-	ret := clone(gs)
-	ret.State++
-	return ret
-}
-
-func EndHand(gs GameState) GameState {
-	ret := clone(gs)
-	pScore, dScore := ret.Player.Score(), ret.Dealer.Score()
+func (p *player) Results(hands [][]deck.Card, dealer []deck.Card) {
+	dScore := blackjack.Score(dealer...)
 	fmt.Println("==FINAL HANDS==")
-	fmt.Println("Player:", ret.Player, "\nScore:", pScore)
-	fmt.Println("Dealer:", ret.Dealer, "\nScore:", dScore)
+	for i, hand := range hands {
+		fmt.Println("Player:", handString(hand), "\nScore:", blackjack.Score(hand...))
+		if i < len(p.outcomes) {
+			fmt.Println(outcomeMessage(p.outcomes[i], blackjack.Score(hand...), dScore))
+		}
+	}
+	fmt.Println("Dealer:", handString(dealer), "\nScore:", dScore)
 	switch {
-	case pScore > 21:
-		fmt.Println("You busted")
-	case dScore > 21:
-		fmt.Println("Dealer busted")
-	case pScore > dScore:
-		fmt.Println("You win!")
-	case dScore > pScore:
-		fmt.Println("You lose")
-	case pScore == dScore:
-		fmt.Println("Draw")
+	case p.insurance == nil:
+	case *p.insurance > 0:
+		fmt.Println("Insurance won", *p.insurance)
+	default:
+		fmt.Println("Insurance lost", -*p.insurance)
 	}
 	fmt.Println()
-	ret.Player = nil // clear out hands
-	ret.Dealer = nil
-	return ret
-}
-
-type State uint8
-
-const (
-	StatePlayerTurn State = iota
-	StateDealerTurn
-	StateHandOver
-)
-
-type GameState struct {
-	Deck   []deck.Card
-	State  State
-	Player Hand
-	Dealer Hand
 }
 
-func (gs *GameState) CurrentPlayer() *Hand {
-	switch gs.State {
-	case StatePlayerTurn:
-		return &gs.Player
-	case StateDealerTurn:
-		return &gs.Dealer
-	default: // shouldn't ever happen, if so, there's a bug
-		panic("it isn't currently any player's turn.")
+func outcomeMessage(outcome blackjack.Outcome, pScore, dScore int) string {
+	switch {
+	case outcome == blackjack.OutcomeBlackjack:
+		return "Blackjack!"
+	case outcome == blackjack.OutcomeSurrender:
+		return "You surrendered"
+	case pScore > 21:
+		return "You busted"
+	case outcome == blackjack.OutcomePush:
+		return "Draw"
+	case dScore > 21:
+		return "Dealer busted"
+	case outcome == blackjack.OutcomeWin:
+		return "You win!"
+	default:
+		return "You lose"
 	}
 }
 
-func clone(gs GameState) GameState {
-	ret := GameState{
-		Deck:   make([]deck.Card, len(gs.Deck)),
-		State:  gs.State,
-		Player: make(Hand, len(gs.Player)),
-		Dealer: make(Hand, len(gs.Dealer)),
+func main() {
+	game := blackjack.New(blackjack.Options{
+		Decks: 3,
+		Hands: 1,
+	})
+	if _, err := game.Play(blackjack.Seat{AI: &player{game: &game}}); err != nil {
+		fmt.Println("Error:", err)
 	}
-	// ensure we deep copy the underlying array from slices
-	copy(ret.Deck, gs.Deck)
-	copy(ret.Player, gs.Player)
-	copy(ret.Dealer, gs.Dealer)
-	return ret
 }
@@ -0,0 +1,194 @@
+// Package betting sizes the bets of a blackjack.AI from its bankroll and its recent results, with the usual
+// progressions and Kelly betting on the true count.
+package betting
+
+import (
+	"math"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/blackjack_ai/counting"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Session is what a strategy knows when sizing the next bet.
+type Session struct {
+	Bankroll int     // the current balance
+	Rounds   int     // the number of rounds played so far
+	Last     int     // the previous bet, 0 before the first round
+	Net      int     // the net winnings of the previous round
+	Streak   int     // the number of rounds in a row won (if positive) or lost (if negative); pushes don't count
+	Count    float64 // the true count, or 0 without a counter
+}
+
+// Strategy sizes bets. The bet is kept within the table limits and the bankroll afterwards, so a strategy
+// doesn't have to.
+type Strategy interface {
+	Bet(s Session) int
+}
+
+// Flat bets the same every round.
+type Flat struct {
+	Unit int
+}
+
+func (f Flat) Bet(s Session) int {
+	return f.Unit
+}
+
+// Martingale doubles the bet after every loss, going back to a single unit after a win.
+type Martingale struct {
+	Unit int
+}
+
+func (m Martingale) Bet(s Session) int {
+	switch {
+	case s.Last == 0 || s.Net > 0:
+		return m.Unit
+	case s.Net < 0:
+		return 2 * s.Last
+	}
+	return s.Last
+}
+
+// Paroli doubles the bet after every win, going back to a single unit after a loss or once Wins wins in a
+// row have been doubled up.
+type Paroli struct {
+	Unit int
+	Wins int // defaults to 3
+}
+
+func (p Paroli) Bet(s Session) int {
+	wins := p.Wins
+	if wins == 0 {
+		wins = 3
+	}
+	switch {
+	case s.Last == 0 || s.Net < 0 || s.Streak >= wins:
+		return p.Unit
+	case s.Net > 0:
+		return 2 * s.Last
+	}
+	return s.Last
+}
+
+// Kelly bets a fraction of the bankroll in proportion to the player's edge, estimated from the true count.
+// When the player has no edge it bets Unit, usually the table minimum.
+type Kelly struct {
+	Unit     int
+	Fraction float64 // the fraction of the full Kelly bet, e.g. 0.5 for half Kelly; defaults to 1
+	// Edge returns the player's edge at a true count; defaults to -0.5% plus 0.5% per count, the usual
+	// estimate for a shoe game.
+	Edge func(count float64) float64
+	// Variance is the variance of a round's result per unit bet; defaults to 1.3.
+	Variance float64
+}
+
+func (k Kelly) Bet(s Session) int {
+	fraction, variance, edge := k.Fraction, k.Variance, k.Edge
+	if fraction == 0 {
+		fraction = 1
+	}
+	if variance == 0 {
+		variance = 1.3
+	}
+	if edge == nil {
+		edge = func(count float64) float64 { return -0.005 + 0.005*count }
+	}
+	e := edge(s.Count)
+	if e <= 0 {
+		return k.Unit
+	}
+	bet := int(math.Floor(fraction * e / variance * float64(s.Bankroll)))
+	if bet < k.Unit {
+		return k.Unit
+	}
+	return bet
+}
+
+// AI wraps another blackjack.AI, which still makes every playing decision, and sizes its bets with the
+// strategy. It keeps track of its bankroll through blackjack.BankrollObserver, and of the count if given
+// a counter.
+type AI struct {
+	blackjack.AI
+	Strategy Strategy
+	Counter  *counting.Counter // optional, for strategies which bet on the count
+	Min, Max int               // the table limits; Min defaults to 1 and a Max of 0 means there's no maximum
+	// CapToBankroll keeps bets to the balance while it covers the table minimum, as a seat which stops when
+	// broke must.
+	CapToBankroll bool
+
+	session Session
+	seen    bool // a balance has been observed
+}
+
+// ObserveBankroll implements blackjack.BankrollObserver, working out the last round's result from the
+// change in balance and passing the balance on to the wrapped AI if it is an observer as well.
+func (ai *AI) ObserveBankroll(balance int) {
+	s := &ai.session
+	if ai.seen && s.Last > 0 {
+		s.Rounds++
+		s.Net = balance - s.Bankroll
+		switch {
+		case s.Net > 0 && s.Streak >= 0:
+			s.Streak++
+		case s.Net > 0:
+			s.Streak = 1
+		case s.Net < 0 && s.Streak <= 0:
+			s.Streak--
+		case s.Net < 0:
+			s.Streak = -1
+		}
+	}
+	s.Bankroll = balance
+	ai.seen = true
+	if o, ok := ai.AI.(blackjack.BankrollObserver); ok {
+		o.ObserveBankroll(balance)
+	}
+}
+
+// Bet resets the count when the shoe was shuffled and bets according to the strategy, kept within the table
+// limits. The wrapped AI's Bet is still called, so it can
+// prepare for the round, but its bet is ignored.
+func (ai *AI) Bet(shuffled bool) int {
+	if ai.Counter != nil {
+		if shuffled {
+			ai.Counter.Reset()
+		}
+		ai.session.Count = ai.Counter.TrueCount()
+	}
+	ai.AI.Bet(shuffled)
+	min := ai.Min
+	if min == 0 {
+		min = 1
+	}
+	bet := ai.Strategy.Bet(ai.session)
+	if ai.CapToBankroll && bet > ai.session.Bankroll && ai.session.Bankroll >= min {
+		bet = ai.session.Bankroll
+	}
+	if ai.Max > 0 && bet > ai.Max {
+		bet = ai.Max
+	}
+	if bet < min {
+		bet = min
+	}
+	ai.session.Last = bet
+	return bet
+}
+
+// ObserveCard implements blackjack.CardObserver, counting the card if there's a counter and passing it on
+// to the wrapped AI if it is an observer as well.
+func (ai *AI) ObserveCard(card deck.Card) {
+	if ai.Counter != nil {
+		ai.Counter.Observe(card)
+	}
+	if o, ok := ai.AI.(blackjack.CardObserver); ok {
+		o.ObserveCard(card)
+	}
+}
+
+// ObserveEvent implements blackjack.Observer, passing the event on to the wrapped AI if it is an observer.
+func (ai *AI) ObserveEvent(e blackjack.Event) {
+	if o, ok := ai.AI.(blackjack.Observer); ok {
+		o.ObserveEvent(e)
+	}
+}
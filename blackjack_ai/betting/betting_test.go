@@ -0,0 +1,122 @@
+package betting
+
+import (
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/blackjack_ai/counting"
+)
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		session  Session
+		want     int
+	}{
+		{"flat", Flat{Unit: 10}, Session{Last: 40, Net: -40}, 10},
+		{"martingale first bet", Martingale{Unit: 10}, Session{}, 10},
+		{"martingale after a loss", Martingale{Unit: 10}, Session{Last: 20, Net: -20, Streak: -2}, 40},
+		{"martingale after a win", Martingale{Unit: 10}, Session{Last: 40, Net: 40, Streak: 1}, 10},
+		{"martingale after a push", Martingale{Unit: 10}, Session{Last: 40, Streak: -2}, 40},
+		{"paroli after a win", Paroli{Unit: 10}, Session{Last: 20, Net: 20, Streak: 2}, 40},
+		{"paroli after three wins", Paroli{Unit: 10}, Session{Last: 40, Net: 40, Streak: 3}, 10},
+		{"paroli after a loss", Paroli{Unit: 10}, Session{Last: 20, Net: -20, Streak: -1}, 10},
+		{"kelly without an edge", Kelly{Unit: 10}, Session{Bankroll: 10000, Count: 1}, 10},
+		{"kelly at +3", Kelly{Unit: 10}, Session{Bankroll: 10000, Count: 3}, 76},
+		{"half kelly at +3", Kelly{Unit: 10, Fraction: 0.5}, Session{Bankroll: 10000, Count: 3}, 38},
+	}
+	for _, tt := range tests {
+		if got := tt.strategy.Bet(tt.session); got != tt.want {
+			t.Errorf("%s: want %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestAI(t *testing.T) {
+	ai := &AI{
+		AI:            blackjack.BasicStrategy(nil, blackjack.Options{}),
+		Strategy:      Martingale{Unit: 10},
+		Min:           5,
+		Max:           100,
+		CapToBankroll: true,
+	}
+	// each balance is observed before the bet after it
+	steps := []struct {
+		balance int
+		want    int
+	}{
+		{200, 10},
+		{190, 20},
+		{170, 40},
+		{130, 80},
+		{50, 50}, // capped to the balance
+		{100, 10},
+		{110, 10},
+		{100, 20},
+		{80, 40},
+	}
+	for i, step := range steps {
+		ai.ObserveBankroll(step.balance)
+		if got := ai.Bet(false); got != step.want {
+			t.Errorf("step %d: want a bet of %d, got %d", i, step.want, got)
+		}
+	}
+	if s := ai.session; s.Rounds != 8 || s.Streak != -2 {
+		t.Errorf("want 8 rounds ending with two losses, got %+v", s)
+	}
+
+	ai = &AI{AI: blackjack.BasicStrategy(nil, blackjack.Options{}), Strategy: Martingale{Unit: 10}, Max: 30}
+	for i, step := range []struct {
+		balance int
+		want    int
+	}{{0, 10}, {-10, 20}, {-30, 30}} {
+		ai.ObserveBankroll(step.balance)
+		if got := ai.Bet(false); got != step.want {
+			t.Errorf("uncapped step %d: want a bet of %d, got %d", i, step.want, got)
+		}
+	}
+}
+
+func TestPlay(t *testing.T) {
+	opts := blackjack.Options{Decks: 6, Hands: 5000, Seed: 7, MinBet: 10, MaxBet: 500}
+	strategies := []Strategy{Flat{Unit: 10}, Martingale{Unit: 10}, Paroli{Unit: 10}, Kelly{Unit: 10, Fraction: 0.5}}
+	for _, s := range strategies {
+		game := blackjack.New(opts)
+		ai := &AI{
+			AI:            blackjack.BasicStrategy(nil, opts),
+			Strategy:      s,
+			Counter:       counting.NewCounter(counting.HiLo, opts.Decks),
+			Min:           opts.MinBet,
+			Max:           opts.MaxBet,
+			CapToBankroll: true,
+		}
+		results, err := game.Play(blackjack.Seat{AI: ai, Bankroll: 1000, Stop: blackjack.Stop{Broke: true, Goal: 2000}})
+		if err != nil {
+			t.Errorf("%T: unexpected error: %v", s, err)
+			continue
+		}
+		if r := results[0]; r.Stopped == blackjack.StopNone && r.Rounds != opts.Hands {
+			t.Errorf("%T: want every round played without stopping, got %+v", s, r)
+		}
+	}
+}
+
+// eventAI plays basic strategy, counting the events it observes.
+type eventAI struct {
+	blackjack.AI
+	events int
+}
+
+func (ai *eventAI) ObserveEvent(e blackjack.Event) { ai.events++ }
+
+func TestAIForwardsEvents(t *testing.T) {
+	inner := &eventAI{AI: blackjack.BasicStrategy(nil, blackjack.Options{})}
+	game := blackjack.New(blackjack.Options{Hands: 3, Seed: 1})
+	if _, err := game.Play(blackjack.Seat{AI: &AI{AI: inner, Strategy: Flat{Unit: 10}}, Bankroll: 100}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if inner.events == 0 {
+		t.Error("want the wrapped AI to observe the game's events")
+	}
+}
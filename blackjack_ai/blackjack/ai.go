@@ -7,12 +7,38 @@ import (
 )
 
 type AI interface {
+	// Bet is asked for the bet at the start of each round, or LeaveTable to leave the table instead.
 	Bet(shuffled bool) int
 	Play(hand []deck.Card, dealer deck.Card) Move
+	// Insurance is asked whether to take insurance when the dealer shows an ace. When hand is a blackjack
+	// this is the offer of even money.
+	Insurance(hand []deck.Card) bool
+	// Surrender is asked whether to give up the hand for half of the bet before the dealer checks for
+	// blackjack. It's only asked when the table allows early surrender and the dealer shows an ace or a ten.
+	Surrender(hand []deck.Card, dealer deck.Card) bool
 	Results(hand [][]deck.Card, dealer []deck.Card)
 }
 
-type dealerAI struct{}
+// LeaveTable is the bet an AI makes to leave the table. The seat stops with StopLeft, like any other stop
+// condition, rather than the game ending with an error.
+const LeaveTable = -1
+
+// CardObserver is an optional interface an AI can implement to be shown every card as it becomes
+// visible at the table: both players' and dealer's cards as they are dealt, and the dealer's hole card
+// once it is turned over. This is what card counting strategies need.
+type CardObserver interface {
+	ObserveCard(card deck.Card)
+}
+
+// BankrollObserver is an optional interface an AI can implement to be told its seat's balance before
+// every bet, so it can size its bets to its bankroll.
+type BankrollObserver interface {
+	ObserveBankroll(balance int)
+}
+
+type dealerAI struct {
+	hitSoft17 bool
+}
 
 func (ai dealerAI) Bet(shuffled bool) int {
 	// noop
@@ -21,12 +47,22 @@ func (ai dealerAI) Bet(shuffled bool) int {
 
 func (ai dealerAI) Play(hand []deck.Card, dealer deck.Card) Move {
 	dScore := Score(hand...)
-	if dScore < 16 || (dScore == 17 && Soft(hand...)) { // dealer hits if < 16 or soft 17 (ace)
+	if dScore < 17 || (ai.hitSoft17 && dScore == 17 && Soft(hand...)) { // dealer hits if < 17, or soft 17 (ace) under H17
 		return MoveHit
 	}
 	return MoveStand
 }
 
+func (ai dealerAI) Insurance(hand []deck.Card) bool {
+	// noop
+	return false
+}
+
+func (ai dealerAI) Surrender(hand []deck.Card, dealer deck.Card) bool {
+	// noop
+	return false
+}
+
 func (ai dealerAI) Results(hand [][]deck.Card, dealer []deck.Card) {
 	// noop
 }
@@ -51,7 +87,7 @@ func (ai humanAI) Play(hand []deck.Card, dealer deck.Card) Move {
 	for {
 		fmt.Println("Player:", hand)
 		fmt.Println("Dealer:", dealer)
-		fmt.Println("What will you do? (h)it, (s)tand, (d)ouble")
+		fmt.Println("What will you do? (h)it, (s)tand, (d)ouble, s(p)lit, su(r)render")
 		var input string
 		fmt.Scanf("%s\n", &input)
 		switch input {
@@ -61,6 +97,49 @@ func (ai humanAI) Play(hand []deck.Card, dealer deck.Card) Move {
 			return MoveStand
 		case "d":
 			return MoveDouble
+		case "p":
+			return MoveSplit
+		case "r":
+			return MoveSurrender
+		default:
+			fmt.Println("Invalid option:", input)
+		}
+	}
+}
+
+func (ai humanAI) Insurance(hand []deck.Card) bool {
+	for {
+		fmt.Println("Player:", hand)
+		if Blackjack(hand...) {
+			fmt.Println("The dealer shows an Ace. Take even money? (y)es, (n)o")
+		} else {
+			fmt.Println("The dealer shows an Ace. Take insurance? (y)es, (n)o")
+		}
+		var input string
+		fmt.Scanf("%s\n", &input)
+		switch input {
+		case "y":
+			return true
+		case "n":
+			return false
+		default:
+			fmt.Println("Invalid option:", input)
+		}
+	}
+}
+
+func (ai humanAI) Surrender(hand []deck.Card, dealer deck.Card) bool {
+	for {
+		fmt.Println("Player:", hand)
+		fmt.Println("Dealer:", dealer)
+		fmt.Println("The dealer hasn't checked for blackjack yet. Surrender half your bet? (y)es, (n)o")
+		var input string
+		fmt.Scanf("%s\n", &input)
+		switch input {
+		case "y":
+			return true
+		case "n":
+			return false
 		default:
 			fmt.Println("Invalid option:", input)
 		}
@@ -71,4 +150,5 @@ func (ai humanAI) Results(hand [][]deck.Card, dealer []deck.Card) {
 	fmt.Println("=== FINAL HANDS ===")
 	fmt.Println("Player:", hand)
 	fmt.Println("Dealer:", dealer)
+	fmt.Println()
 }
@@ -0,0 +1,483 @@
+package blackjack
+
+import (
+	"fmt"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Composition is the number of cards of each blackjack value left in a shoe, from Composition[1] for aces
+// to Composition[10] for tens and faces. Composition[0] is unused.
+type Composition [11]int
+
+// NewComposition returns the composition of a full shoe of the given number of decks.
+func NewComposition(decks int) Composition {
+	var c Composition
+	for v := 1; v <= 9; v++ {
+		c[v] = 4 * decks
+	}
+	c[10] = 16 * decks
+	return c
+}
+
+// Remove takes the cards out of the composition, e.g. once they have been dealt.
+func (c *Composition) Remove(cards ...deck.Card) {
+	for _, card := range cards {
+		c[value(card)]--
+	}
+}
+
+// Total returns the number of cards in the composition.
+func (c Composition) Total() int {
+	n := 0
+	for v := 1; v <= 10; v++ {
+		n += c[v]
+	}
+	return n
+}
+
+// value returns the blackjack value of a card, counting an ace as 1.
+func value(c deck.Card) int {
+	if c.Rank > 10 {
+		return 10
+	}
+	return int(c.Rank)
+}
+
+// DealerOutcome is the probability of each way the dealer's hand can finish.
+type DealerOutcome struct {
+	Totals    [5]float64 // finishing on 17 through 21, not counting blackjack
+	Bust      float64
+	Blackjack float64 // always 0 when the dealer has peeked for blackjack
+}
+
+func (o *DealerOutcome) add(other DealerOutcome, p float64) {
+	for i := range o.Totals {
+		o.Totals[i] += p * other.Totals[i]
+	}
+	o.Bust += p * other.Bust
+	o.Blackjack += p * other.Blackjack
+}
+
+// Analyzer works out exact dealer probabilities and the expected value of the player's options for a shoe
+// composition under a set of rules. The expected values of standing and surrendering are exact, while the
+// options which draw cards make the approximations described by Expect. Results are cached, so an analyzer
+// gets faster the more it's used.
+type Analyzer struct {
+	comp Composition
+	opts Options
+
+	dealer map[dealerKey]DealerOutcome
+	hit    map[playerKey]float64
+}
+
+type dealerKey struct {
+	comp  Composition
+	total int // counting aces as 1
+	ace   bool
+	up    int // the upcard when only it has been dealt, otherwise 0
+}
+
+type playerKey struct {
+	comp  Composition
+	total int
+	ace   bool
+	up    int
+}
+
+// NewAnalyzer returns an analyzer for a shoe with the given composition, before any of the cards being
+// analyzed have been dealt from it. The options' defaults are filled in as New does.
+func NewAnalyzer(comp Composition, opts Options) *Analyzer {
+	g := New(opts)
+	return &Analyzer{
+		comp:   comp,
+		opts:   g.Options(),
+		dealer: make(map[dealerKey]DealerOutcome),
+		hit:    make(map[playerKey]float64),
+	}
+}
+
+// peeks returns true if the dealer checks their hole card for blackjack before the players act, in which
+// case every expectation is worked out knowing the dealer doesn't have blackjack.
+func (a *Analyzer) peeks() bool {
+	return !a.opts.NoHoleCard
+}
+
+// Dealer returns the probabilities of the dealer's final hand given their upcard, with the upcard and any
+// other cards which have been seen removed from the shoe. When the dealer peeks for blackjack the
+// probabilities are given that they didn't have one.
+func (a *Analyzer) Dealer(upcard deck.Card, seen ...deck.Card) DealerOutcome {
+	comp := a.comp
+	comp.Remove(upcard)
+	comp.Remove(seen...)
+	return a.dealerFrom(comp, value(upcard))
+}
+
+func (a *Analyzer) dealerFrom(comp Composition, up int) DealerOutcome {
+	key := dealerKey{comp: comp, total: up, ace: up == 1, up: up}
+	if o, ok := a.dealer[key]; ok {
+		return o
+	}
+	var o DealerOutcome
+	n := float64(comp.Total())
+	excluded := 0.0
+	for v := 1; v <= 10; v++ {
+		if comp[v] == 0 {
+			continue
+		}
+		p := float64(comp[v]) / n
+		if (up == 1 && v == 10) || (up == 10 && v == 1) {
+			if a.peeks() {
+				excluded += p
+			} else {
+				o.Blackjack += p
+			}
+			continue
+		}
+		next := comp
+		next[v]--
+		o.add(a.dealerDraw(next, up+v, up == 1 || v == 1), p)
+	}
+	if excluded > 0 {
+		// given the dealer didn't have blackjack
+		for i := range o.Totals {
+			o.Totals[i] /= 1 - excluded
+		}
+		o.Bust /= 1 - excluded
+	}
+	a.dealer[key] = o
+	return o
+}
+
+// dealerDraw plays out the dealer's hand, which has at least two cards, from the composition left.
+func (a *Analyzer) dealerDraw(comp Composition, total int, ace bool) DealerOutcome {
+	var o DealerOutcome
+	best, soft := bestScore(total, ace)
+	switch {
+	case best > 21:
+		o.Bust = 1
+		return o
+	case best > 17 || (best == 17 && !(soft && !a.opts.StandSoft17)):
+		o.Totals[best-17] = 1
+		return o
+	}
+	key := dealerKey{comp: comp, total: total, ace: ace}
+	if o, ok := a.dealer[key]; ok {
+		return o
+	}
+	n := float64(comp.Total())
+	for v := 1; v <= 10; v++ {
+		if comp[v] == 0 {
+			continue
+		}
+		next := comp
+		next[v]--
+		o.add(a.dealerDraw(next, total+v, ace || v == 1), float64(comp[v])/n)
+	}
+	a.dealer[key] = o
+	return o
+}
+
+// bestScore returns the best score of a hand from its total counting aces as 1, and whether it's soft.
+func bestScore(total int, ace bool) (int, bool) {
+	if ace && total+10 <= 21 {
+		return total + 10, true
+	}
+	return total, false
+}
+
+// Expectation is the expected value, per unit of the original bet, of each of the player's options on a
+// hand, given the dealer doesn't have blackjack when they peek. Options which aren't allowed are left as 0
+// with their Can field false.
+type Expectation struct {
+	Stand     float64
+	Hit       float64
+	Double    float64
+	Split     float64
+	Surrender float64
+
+	CanDouble    bool
+	CanSplit     bool
+	CanSurrender bool
+}
+
+// Best returns the action with the highest expected value, using the chart notation so that it says what
+// to do when the best option isn't allowed, e.g. Ds for doubling when allowed and standing otherwise.
+func (e Expectation) Best() (Action, float64) {
+	action, ev := ActionStand, e.Stand
+	if e.Hit > ev {
+		action, ev = ActionHit, e.Hit
+	}
+	fallback := action
+	if e.CanDouble && e.Double > ev {
+		action, ev = ActionDouble, e.Double
+		if fallback == ActionStand {
+			action = ActionDoubleOrStand
+		}
+	}
+	if e.CanSplit && e.Split > ev {
+		action, ev = ActionSplit, e.Split
+	}
+	if e.CanSurrender && e.Surrender > ev {
+		switch action {
+		case ActionSplit:
+			action = ActionSurrenderOrSplit
+		case ActionStand, ActionDoubleOrStand:
+			action = ActionSurrenderOrStand
+		default:
+			action = ActionSurrender
+		}
+		ev = e.Surrender
+	}
+	return action, ev
+}
+
+// Expect returns the expected value of each option for a hand against the dealer's upcard. Two of them are
+// approximations rather than exact:
+//   - Splits are worked out with each of the two hands played to the end without resplitting, as if the
+//     other hand's cards hadn't been dealt.
+//   - The cards the player draws when hitting, doubling or splitting are dealt from the shoe as if the dealer
+//     hadn't peeked, so they don't account for the hole card not making a blackjack.
+//
+// Early surrender, which also saves half the bet when the dealer has blackjack, is valued as the
+// surrender given no blackjack which would have the same expected value.
+func (a *Analyzer) Expect(hand []deck.Card, upcard deck.Card) Expectation {
+	comp := a.comp
+	comp.Remove(upcard)
+	comp.Remove(hand...)
+	up := value(upcard)
+	total, ace := 0, false
+	for _, c := range hand {
+		total += value(c)
+		ace = ace || c.Rank == deck.Ace
+	}
+	e := Expectation{
+		Stand: a.stand(comp, total, ace, up),
+		Hit:   a.hitEV(comp, total, ace, up),
+	}
+	if len(hand) == 2 {
+		e.CanDouble = a.canDouble(total, ace, false)
+		e.CanSurrender = a.opts.Surrender != SurrenderNone
+		e.Surrender = a.surrenderEV(comp, up)
+	}
+	if e.CanDouble {
+		e.Double = a.doubleEV(comp, total, ace, up)
+	}
+	if len(hand) == 2 && hand[0].Rank == hand[1].Rank && a.opts.MaxSplitHands > 1 {
+		e.CanSplit = true
+		e.Split = a.splitEV(comp, value(hand[0]), up)
+	}
+	return e
+}
+
+func (a *Analyzer) canDouble(total int, ace bool, split bool) bool {
+	if split && a.opts.NoDoubleAfterSplit {
+		return false
+	}
+	score, _ := bestScore(total, ace)
+	switch a.opts.Double {
+	case DoubleNineToEleven:
+		return score >= 9 && score <= 11
+	case DoubleTenToEleven:
+		return score >= 10 && score <= 11
+	}
+	return true
+}
+
+// surrenderEV returns the expected value of surrendering. Late surrender loses half the bet. Early
+// surrender loses half the bet whether or not the dealer has blackjack, where playing on would lose it all,
+// so it's worth more than late surrender once the blackjacks are left out: with p the probability of the
+// dealer's blackjack, it's the value s with -p + (1-p)s = -1/2.
+func (a *Analyzer) surrenderEV(comp Composition, up int) float64 {
+	if a.opts.Surrender != SurrenderEarly || !a.peeks() {
+		return -0.5
+	}
+	p := 0.0
+	n := float64(comp.Total())
+	switch up {
+	case 1:
+		p = float64(comp[10]) / n
+	case 10:
+		p = float64(comp[1]) / n
+	}
+	return (p - 0.5) / (1 - p)
+}
+
+// stand returns the expected value of standing on a hand with the composition left.
+func (a *Analyzer) stand(comp Composition, total int, ace bool, up int) float64 {
+	score, _ := bestScore(total, ace)
+	if score > 21 {
+		return -1
+	}
+	o := a.dealerFrom(comp, up)
+	ev := o.Bust - o.Blackjack
+	for i, p := range o.Totals {
+		switch dealer := 17 + i; {
+		case score > dealer:
+			ev += p
+		case score < dealer:
+			ev -= p
+		}
+	}
+	return ev
+}
+
+// hitEV returns the expected value of hitting a hand and then playing it out as well as possible.
+func (a *Analyzer) hitEV(comp Composition, total int, ace bool, up int) float64 {
+	key := playerKey{comp: comp, total: total, ace: ace, up: up}
+	if ev, ok := a.hit[key]; ok {
+		return ev
+	}
+	ev := 0.0
+	n := float64(comp.Total())
+	for v := 1; v <= 10; v++ {
+		if comp[v] == 0 {
+			continue
+		}
+		p := float64(comp[v]) / n
+		next := comp
+		next[v]--
+		t, c := total+v, ace || v == 1
+		if t > 21 {
+			ev -= p
+			continue
+		}
+		best := a.stand(next, t, c, up)
+		if t < 21 || c {
+			if hit := a.hitEV(next, t, c, up); hit > best {
+				best = hit
+			}
+		}
+		ev += p * best
+	}
+	a.hit[key] = ev
+	return ev
+}
+
+// doubleEV returns the expected value of doubling down, per unit of the original bet.
+func (a *Analyzer) doubleEV(comp Composition, total int, ace bool, up int) float64 {
+	ev := 0.0
+	n := float64(comp.Total())
+	for v := 1; v <= 10; v++ {
+		if comp[v] == 0 {
+			continue
+		}
+		next := comp
+		next[v]--
+		ev += float64(comp[v]) / n * 2 * a.stand(next, total+v, ace || v == 1, up)
+	}
+	return ev
+}
+
+// splitEV returns the expected value of splitting a pair of the given value, per unit of the original bet.
+func (a *Analyzer) splitEV(comp Composition, pair int, up int) float64 {
+	ev := 0.0
+	n := float64(comp.Total())
+	for v := 1; v <= 10; v++ {
+		if comp[v] == 0 {
+			continue
+		}
+		next := comp
+		next[v]--
+		total, ace := pair+v, pair == 1 || v == 1
+		hand := a.stand(next, total, ace, up)
+		if pair != 1 { // split aces only get one card
+			if hit := a.hitEV(next, total, ace, up); hit > hand {
+				hand = hit
+			}
+			if a.canDouble(total, ace, true) {
+				if double := a.doubleEV(next, total, ace, up); double > hand {
+					hand = double
+				}
+			}
+		}
+		ev += float64(comp[v]) / n * hand
+	}
+	return 2 * ev
+}
+
+// DeriveChart works out basic strategy for the analyzer's shoe and rules from the expectations of Expect.
+// Each hard total is the average over every unpaired two card hand making it, weighted by how likely it is
+// to be dealt. Hard 20 and 21 and soft 21 can't be made that way, so they are worked out from a three card
+// hand. As Expect's splits don't resplit, a pair on the line between splitting and not may be played
+// differently from a chart which accounts for resplitting.
+func (a *Analyzer) DeriveChart() *Chart {
+	c := &Chart{
+		Name:  fmt.Sprintf("derived, %d cards", a.comp.Total()),
+		Hard:  make(map[int]Row),
+		Soft:  make(map[int]Row),
+		Pairs: make(map[int]Row),
+	}
+	upcards := make([]deck.Card, 10)
+	for i := range upcards {
+		upcards[i] = card(i + 2)
+	}
+	for total := 5; total <= 21; total++ {
+		var hands [][]deck.Card
+		for low := 2; low < total-low; low++ {
+			if total-low > 10 {
+				continue
+			}
+			hands = append(hands, []deck.Card{card(low), card(total - low)})
+		}
+		if len(hands) == 0 {
+			hands = [][]deck.Card{{card(10), card(total - 14), card(4)}}
+		}
+		c.Hard[total] = a.row(hands, upcards)
+	}
+	for total := 13; total <= 20; total++ {
+		c.Soft[total] = a.row([][]deck.Card{{card(11), card(total - 11)}}, upcards)
+	}
+	c.Soft[21] = a.row([][]deck.Card{{card(11), card(4), card(6)}}, upcards)
+	for v := 2; v <= 11; v++ {
+		c.Pairs[v] = a.row([][]deck.Card{{card(v), card(v)}}, upcards)
+	}
+	return c
+}
+
+// row returns the best action for the hands against each upcard, weighting each hand by its probability.
+func (a *Analyzer) row(hands [][]deck.Card, upcards []deck.Card) Row {
+	var row Row
+	for i, up := range upcards {
+		var sum Expectation
+		weight := 0.0
+		for _, hand := range hands {
+			w := a.probability(append([]deck.Card{up}, hand...))
+			e := a.Expect(hand, up)
+			sum.Stand += w * e.Stand
+			sum.Hit += w * e.Hit
+			sum.Double += w * e.Double
+			sum.Split += w * e.Split
+			sum.Surrender += w * e.Surrender
+			sum.CanDouble, sum.CanSplit, sum.CanSurrender = e.CanDouble, e.CanSplit, e.CanSurrender
+			weight += w
+		}
+		sum.Stand /= weight
+		sum.Hit /= weight
+		sum.Double /= weight
+		sum.Split /= weight
+		sum.Surrender /= weight
+		row[i], _ = sum.Best()
+	}
+	return row
+}
+
+// probability returns the probability of dealing the cards, in any order, from the analyzer's shoe.
+func (a *Analyzer) probability(cards []deck.Card) float64 {
+	comp := a.comp
+	p := 1.0
+	for _, c := range cards {
+		n := comp.Total()
+		p *= float64(comp[value(c)]) / float64(n)
+		comp[value(c)]--
+	}
+	return p
+}
+
+// card returns a card with the given blackjack value, with 11 for an ace. The suit doesn't matter.
+func card(v int) deck.Card {
+	if v == 11 || v == 1 {
+		return deck.Card{Rank: deck.Ace, Suit: deck.Spade}
+	}
+	return deck.Card{Rank: deck.Rank(v), Suit: deck.Spade}
+}
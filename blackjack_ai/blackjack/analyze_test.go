@@ -0,0 +1,118 @@
+package blackjack
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+func TestDealerOutcome(t *testing.T) {
+	for _, opts := range []Options{{}, {StandSoft17: true}, {NoHoleCard: true}} {
+		a := NewAnalyzer(NewComposition(6), opts)
+		for v := 1; v <= 10; v++ {
+			o := a.Dealer(card(v))
+			sum := o.Bust + o.Blackjack
+			for _, p := range o.Totals {
+				sum += p
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("%+v upcard %d: probabilities sum to %v", opts, v, sum)
+			}
+			if !opts.NoHoleCard && o.Blackjack != 0 {
+				t.Errorf("%+v upcard %d: want no blackjack after peeking, got %v", opts, v, o.Blackjack)
+			}
+		}
+	}
+
+	a := NewAnalyzer(NewComposition(6), Options{NoHoleCard: true})
+	if got, want := a.Dealer(card(11)).Blackjack, 96.0/311; math.Abs(got-want) > 1e-12 {
+		t.Errorf("blackjack under an ace: want %v, got %v", want, got)
+	}
+	// hitting soft 17 turns some 17s into other totals
+	h17 := NewAnalyzer(NewComposition(6), Options{}).Dealer(card(6))
+	s17 := NewAnalyzer(NewComposition(6), Options{StandSoft17: true}).Dealer(card(6))
+	if h17.Totals[0] >= s17.Totals[0] || h17.Bust <= s17.Bust {
+		t.Errorf("upcard 6: want fewer 17s and more busts hitting soft 17, got H17 %+v, S17 %+v", h17, s17)
+	}
+}
+
+func TestExpect(t *testing.T) {
+	a := NewAnalyzer(NewComposition(6), Options{StandSoft17: true, Surrender: SurrenderLate})
+	tests := []struct {
+		hand   string
+		dealer string
+		want   Action
+	}{
+		{"10S 6H", "10D", ActionSurrender},
+		{"10S 6H", "6D", ActionStand},
+		{"5S 6H", "6D", ActionDouble},
+		{"AS 7H", "3D", ActionDoubleOrStand},
+		{"AS 7H", "9D", ActionHit},
+		{"8S 8H", "10D", ActionSplit},
+		{"10S 10H", "6D", ActionStand},
+		{"7S 5H", "4D", ActionStand},
+		{"10S 2H", "3D", ActionHit},
+	}
+	for _, tt := range tests {
+		hand, _ := deck.ParseHand(tt.hand)
+		dealer, _ := deck.ParseCard(tt.dealer)
+		if got, _ := a.Expect(hand, dealer).Best(); got != tt.want {
+			t.Errorf("%s against %s: want %q, got %q", tt.hand, tt.dealer, tt.want, got)
+		}
+	}
+	// 16 against a 10 is famously close between standing and hitting
+	hand, _ := deck.ParseHand("10S 6H")
+	dealer, _ := deck.ParseCard("10D")
+	e := a.Expect(hand, dealer)
+	if e.Stand < -0.55 || e.Stand > -0.53 || e.Hit < e.Stand || e.Hit-e.Stand > 0.01 {
+		t.Errorf("16 against a 10: want stand about -0.54 and hit slightly better, got %+v", e)
+	}
+}
+
+func TestDeriveChart(t *testing.T) {
+	if testing.Short() {
+		t.Skip("deriving a chart takes a few seconds")
+	}
+	opts := Options{Decks: 6, StandSoft17: true, Surrender: SurrenderLate}
+	derived := NewAnalyzer(NewComposition(6), opts).DeriveChart()
+	check := func(table string, want, got map[int]Row) {
+		for total, row := range want {
+			for i := range row {
+				if got[total][i].For(opts) != row[i].For(opts) {
+					t.Errorf("%s %d against upcard %d: want %q, got %q", table, total, i+2, row[i], got[total][i])
+				}
+			}
+		}
+	}
+	check("hard", ChartS17.Hard, derived.Hard)
+	check("soft", ChartS17.Soft, derived.Soft)
+	check("pair", ChartS17.Pairs, derived.Pairs)
+}
+
+func TestExpectEarlySurrender(t *testing.T) {
+	late := NewAnalyzer(NewComposition(6), Options{StandSoft17: true, Surrender: SurrenderLate})
+	early := NewAnalyzer(NewComposition(6), Options{StandSoft17: true, Surrender: SurrenderEarly})
+	hand, _ := deck.ParseHand("10S 4H")
+	dealer, _ := deck.ParseCard("AD")
+	// of the 309 cards left, 95 are tens which give the dealer blackjack
+	p := 95.0 / 309
+	if got, want := early.Expect(hand, dealer).Surrender, (p-0.5)/(1-p); math.Abs(got-want) > 1e-12 {
+		t.Errorf("early surrender against an ace: want %v, got %v", want, got)
+	}
+	if got := late.Expect(hand, dealer).Surrender; got != -0.5 {
+		t.Errorf("late surrender against an ace: want -0.5, got %v", got)
+	}
+	// 14 against an ace is only worth surrendering before the dealer peeks
+	if got, _ := late.Expect(hand, dealer).Best(); got != ActionHit {
+		t.Errorf("14 against an ace with late surrender: want %q, got %q", ActionHit, got)
+	}
+	if got, _ := early.Expect(hand, dealer).Best(); got != ActionSurrender {
+		t.Errorf("14 against an ace with early surrender: want %q, got %q", ActionSurrender, got)
+	}
+	// with no blackjack possible under a 9, early surrender is the same as late
+	dealer, _ = deck.ParseCard("9D")
+	if got := early.Expect(hand, dealer).Surrender; got != -0.5 {
+		t.Errorf("early surrender against a 9: want -0.5, got %v", got)
+	}
+}
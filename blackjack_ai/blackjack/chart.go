@@ -0,0 +1,394 @@
+package blackjack
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jeremy-miller/gophercises/deck"
+	"gopkg.in/yaml.v2"
+)
+
+// Action is an entry in a strategy chart, using the usual chart notation.
+type Action string
+
+const (
+	ActionHit              Action = "H"
+	ActionStand            Action = "S"
+	ActionDouble           Action = "D"  // double if allowed, otherwise hit
+	ActionDoubleOrStand    Action = "Ds" // double if allowed, otherwise stand
+	ActionSplit            Action = "P"  // split if allowed, otherwise play the hand's total
+	ActionSplitIfDAS       Action = "Ph" // split if doubling after splitting is allowed, otherwise hit
+	ActionSurrender        Action = "Rh" // surrender if allowed, otherwise hit
+	ActionSurrenderOrStand Action = "Rs" // surrender if allowed, otherwise stand
+	ActionSurrenderOrSplit Action = "Rp" // surrender if allowed, otherwise split
+)
+
+func (a Action) valid() bool {
+	switch a {
+	case ActionHit, ActionStand, ActionDouble, ActionDoubleOrStand, ActionSplit, ActionSplitIfDAS,
+		ActionSurrender, ActionSurrenderOrStand, ActionSurrenderOrSplit:
+		return true
+	}
+	return false
+}
+
+// For returns the action taken under the table's rules, resolving the actions which depend on whether
+// doubling after splitting or surrendering is allowed. Doubling is left as is, since whether a hand can
+// be doubled also depends on its total.
+func (a Action) For(opts Options) Action {
+	switch a {
+	case ActionSplitIfDAS:
+		if opts.NoDoubleAfterSplit {
+			return ActionHit
+		}
+		return ActionSplit
+	case ActionSurrender:
+		if opts.Surrender == SurrenderNone {
+			return ActionHit
+		}
+	case ActionSurrenderOrStand:
+		if opts.Surrender == SurrenderNone {
+			return ActionStand
+		}
+	case ActionSurrenderOrSplit:
+		if opts.Surrender == SurrenderNone {
+			return ActionSplit
+		}
+	}
+	return a
+}
+
+// Row holds the action for each dealer upcard, from 2 through 10 and then the ace.
+type Row [10]Action
+
+// Chart is a basic strategy chart. Hard totals run from 5 to 21, soft totals from 13 (A-2) to 21, and pairs
+// are indexed by the value of one card of the pair, from 2 through 10 with 11 for a pair of aces.
+type Chart struct {
+	Name  string
+	Hard  map[int]Row
+	Soft  map[int]Row
+	Pairs map[int]Row
+}
+
+// upcardIndex returns the column of a chart for the dealer's upcard.
+func upcardIndex(c deck.Card) int {
+	if c.Rank == deck.Ace {
+		return 9
+	}
+	return Score(c) - 2
+}
+
+// Lookup returns the chart's action for a hand against the dealer's upcard. Pairs are looked up in the
+// pair table; pass pair as false to look up a pair by its total instead (e.g. when it can't be split).
+func (c *Chart) Lookup(hand []deck.Card, dealer deck.Card, pair bool) Action {
+	col := upcardIndex(dealer)
+	if pair && len(hand) == 2 && hand[0].Rank == hand[1].Rank {
+		value := Score(hand[0])
+		if hand[0].Rank == deck.Ace {
+			value = 11
+		}
+		return c.Pairs[value][col]
+	}
+	score := Score(hand...)
+	switch {
+	case score > 21:
+		return ActionStand
+	case Soft(hand...) && score >= 13:
+		// a soft 12 is only ever a pair of aces which can't be split, and plays like a hard 12
+		return c.Soft[score][col]
+	case score < 5:
+		return c.Hard[5][col]
+	default:
+		return c.Hard[score][col]
+	}
+}
+
+func (c *Chart) validate() error {
+	check := func(table string, rows map[int]Row, from, to int) error {
+		for total := from; total <= to; total++ {
+			row, ok := rows[total]
+			if !ok {
+				return fmt.Errorf("chart %q is missing %s %d", c.Name, table, total)
+			}
+			for _, a := range row {
+				if !a.valid() {
+					return fmt.Errorf("chart %q has invalid action %q for %s %d", c.Name, a, table, total)
+				}
+			}
+		}
+		return nil
+	}
+	if err := check("hard", c.Hard, 5, 21); err != nil {
+		return err
+	}
+	if err := check("soft", c.Soft, 13, 21); err != nil {
+		return err
+	}
+	return check("pair", c.Pairs, 2, 11)
+}
+
+// LoadChart reads a chart from a CSV or YAML file, chosen by the file's extension.
+func LoadChart(path string) (*Chart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ReadChartCSV(name, f)
+	case ".yaml", ".yml":
+		return ReadChartYAML(f)
+	default:
+		return nil, fmt.Errorf("unknown chart format %q", filepath.Ext(path))
+	}
+}
+
+// ReadChartCSV reads a chart with one row per line: the table (hard, soft or pair), the total (or the card
+// value for pairs, with A for aces), then the action against each upcard from 2 to A. Lines starting with
+// # are ignored, as is a header line.
+func ReadChartCSV(name string, r io.Reader) (*Chart, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	c := &Chart{
+		Name:  name,
+		Hard:  make(map[int]Row),
+		Soft:  make(map[int]Row),
+		Pairs: make(map[int]Row),
+	}
+	for i, rec := range records {
+		if i == 0 && strings.EqualFold(rec[0], "table") {
+			continue
+		}
+		if len(rec) != 12 {
+			return nil, fmt.Errorf("chart %q line %d: want 12 fields, got %d", name, i+1, len(rec))
+		}
+		var rows map[int]Row
+		switch strings.ToLower(rec[0]) {
+		case "hard":
+			rows = c.Hard
+		case "soft":
+			rows = c.Soft
+		case "pair", "pairs":
+			rows = c.Pairs
+		default:
+			return nil, fmt.Errorf("chart %q line %d: unknown table %q", name, i+1, rec[0])
+		}
+		total, err := parseTotal(rec[1])
+		if err != nil {
+			return nil, fmt.Errorf("chart %q line %d: %v", name, i+1, err)
+		}
+		var row Row
+		for j := range row {
+			row[j] = Action(rec[j+2])
+		}
+		rows[total] = row
+	}
+	return c, c.validate()
+}
+
+func parseTotal(s string) (int, error) {
+	if strings.EqualFold(s, "A") {
+		return 11, nil
+	}
+	total, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid total %q", s)
+	}
+	return total, nil
+}
+
+// yamlChart is the layout of a chart in YAML, e.g.
+//
+//	name: my chart
+//	hard:
+//	  16: [S, S, S, S, S, H, H, Rh, Rh, Rh]
+//	pairs:
+//	  A: [P, P, P, P, P, P, P, P, P, P]
+type yamlChart struct {
+	Name  string              `yaml:"name"`
+	Hard  map[string][]string `yaml:"hard"`
+	Soft  map[string][]string `yaml:"soft"`
+	Pairs map[string][]string `yaml:"pairs"`
+}
+
+// ReadChartYAML reads a chart from YAML with a name and hard, soft and pairs tables, each mapping a total
+// to the list of actions against the upcards 2 to A.
+func ReadChartYAML(r io.Reader) (*Chart, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var yc yamlChart
+	if err := yaml.Unmarshal(b, &yc); err != nil {
+		return nil, err
+	}
+	c := &Chart{Name: yc.Name}
+	tables := []struct {
+		in  map[string][]string
+		out *map[int]Row
+	}{
+		{yc.Hard, &c.Hard}, {yc.Soft, &c.Soft}, {yc.Pairs, &c.Pairs},
+	}
+	for _, t := range tables {
+		*t.out = make(map[int]Row)
+		for key, actions := range t.in {
+			total, err := parseTotal(key)
+			if err != nil {
+				return nil, fmt.Errorf("chart %q: %v", c.Name, err)
+			}
+			if len(actions) != 10 {
+				return nil, fmt.Errorf("chart %q: want 10 actions for %s, got %d", c.Name, key, len(actions))
+			}
+			var row Row
+			for j := range row {
+				row[j] = Action(actions[j])
+			}
+			(*t.out)[total] = row
+		}
+	}
+	return c, c.validate()
+}
+
+// WriteCSV writes the chart in the format read by ReadChartCSV.
+func (c *Chart) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"table", "total", "2", "3", "4", "5", "6", "7", "8", "9", "10", "A"})
+	tables := []struct {
+		name     string
+		rows     map[int]Row
+		from, to int
+	}{
+		{"hard", c.Hard, 5, 21}, {"soft", c.Soft, 13, 21}, {"pair", c.Pairs, 2, 11},
+	}
+	for _, t := range tables {
+		for total := t.from; total <= t.to; total++ {
+			rec := []string{t.name, strconv.Itoa(total)}
+			if t.name == "pair" && total == 11 {
+				rec[1] = "A"
+			}
+			for _, a := range t.rows[total] {
+				rec = append(rec, string(a))
+			}
+			cw.Write(rec)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// mustChart parses a built in chart.
+func mustChart(name, data string) *Chart {
+	c, err := ReadChartCSV(name, strings.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// ChartFor returns the built in chart which best matches the table's rules.
+func ChartFor(opts Options) *Chart {
+	if opts.StandSoft17 {
+		return ChartS17
+	}
+	return ChartH17
+}
+
+var (
+	// ChartS17 is basic strategy for 4 to 8 decks where the dealer stands on soft 17, doubling after
+	// splitting is allowed and late surrender is offered.
+	ChartS17 = mustChart("4-8 decks, S17, DAS, late surrender", chartS17)
+	// ChartH17 is basic strategy for 4 to 8 decks where the dealer hits soft 17, doubling after
+	// splitting is allowed and late surrender is offered.
+	ChartH17 = mustChart("4-8 decks, H17, DAS, late surrender", chartH17)
+)
+
+const chartS17 = `table,total,2,3,4,5,6,7,8,9,10,A
+hard,5,H,H,H,H,H,H,H,H,H,H
+hard,6,H,H,H,H,H,H,H,H,H,H
+hard,7,H,H,H,H,H,H,H,H,H,H
+hard,8,H,H,H,H,H,H,H,H,H,H
+hard,9,H,D,D,D,D,H,H,H,H,H
+hard,10,D,D,D,D,D,D,D,D,H,H
+hard,11,D,D,D,D,D,D,D,D,D,H
+hard,12,H,H,S,S,S,H,H,H,H,H
+hard,13,S,S,S,S,S,H,H,H,H,H
+hard,14,S,S,S,S,S,H,H,H,H,H
+hard,15,S,S,S,S,S,H,H,H,Rh,H
+hard,16,S,S,S,S,S,H,H,Rh,Rh,Rh
+hard,17,S,S,S,S,S,S,S,S,S,S
+hard,18,S,S,S,S,S,S,S,S,S,S
+hard,19,S,S,S,S,S,S,S,S,S,S
+hard,20,S,S,S,S,S,S,S,S,S,S
+hard,21,S,S,S,S,S,S,S,S,S,S
+soft,13,H,H,H,D,D,H,H,H,H,H
+soft,14,H,H,H,D,D,H,H,H,H,H
+soft,15,H,H,D,D,D,H,H,H,H,H
+soft,16,H,H,D,D,D,H,H,H,H,H
+soft,17,H,D,D,D,D,H,H,H,H,H
+soft,18,S,Ds,Ds,Ds,Ds,S,S,H,H,H
+soft,19,S,S,S,S,S,S,S,S,S,S
+soft,20,S,S,S,S,S,S,S,S,S,S
+soft,21,S,S,S,S,S,S,S,S,S,S
+pair,2,Ph,Ph,P,P,P,P,H,H,H,H
+pair,3,Ph,Ph,P,P,P,P,H,H,H,H
+pair,4,H,H,H,Ph,Ph,H,H,H,H,H
+pair,5,D,D,D,D,D,D,D,D,H,H
+pair,6,Ph,P,P,P,P,H,H,H,H,H
+pair,7,P,P,P,P,P,P,H,H,H,H
+pair,8,P,P,P,P,P,P,P,P,P,P
+pair,9,P,P,P,P,P,S,P,P,S,S
+pair,10,S,S,S,S,S,S,S,S,S,S
+pair,A,P,P,P,P,P,P,P,P,P,P
+`
+
+const chartH17 = `table,total,2,3,4,5,6,7,8,9,10,A
+hard,5,H,H,H,H,H,H,H,H,H,H
+hard,6,H,H,H,H,H,H,H,H,H,H
+hard,7,H,H,H,H,H,H,H,H,H,H
+hard,8,H,H,H,H,H,H,H,H,H,H
+hard,9,H,D,D,D,D,H,H,H,H,H
+hard,10,D,D,D,D,D,D,D,D,H,H
+hard,11,D,D,D,D,D,D,D,D,D,D
+hard,12,H,H,S,S,S,H,H,H,H,H
+hard,13,S,S,S,S,S,H,H,H,H,H
+hard,14,S,S,S,S,S,H,H,H,H,H
+hard,15,S,S,S,S,S,H,H,H,Rh,Rh
+hard,16,S,S,S,S,S,H,H,Rh,Rh,Rh
+hard,17,S,S,S,S,S,S,S,S,S,Rs
+hard,18,S,S,S,S,S,S,S,S,S,S
+hard,19,S,S,S,S,S,S,S,S,S,S
+hard,20,S,S,S,S,S,S,S,S,S,S
+hard,21,S,S,S,S,S,S,S,S,S,S
+soft,13,H,H,H,D,D,H,H,H,H,H
+soft,14,H,H,H,D,D,H,H,H,H,H
+soft,15,H,H,D,D,D,H,H,H,H,H
+soft,16,H,H,D,D,D,H,H,H,H,H
+soft,17,H,D,D,D,D,H,H,H,H,H
+soft,18,Ds,Ds,Ds,Ds,Ds,S,S,H,H,H
+soft,19,S,S,S,S,Ds,S,S,S,S,S
+soft,20,S,S,S,S,S,S,S,S,S,S
+soft,21,S,S,S,S,S,S,S,S,S,S
+pair,2,Ph,Ph,P,P,P,P,H,H,H,H
+pair,3,Ph,Ph,P,P,P,P,H,H,H,H
+pair,4,H,H,H,Ph,Ph,H,H,H,H,H
+pair,5,D,D,D,D,D,D,D,D,H,H
+pair,6,Ph,P,P,P,P,H,H,H,H,H
+pair,7,P,P,P,P,P,P,H,H,H,H
+pair,8,P,P,P,P,P,P,P,P,P,Rp
+pair,9,P,P,P,P,P,S,P,P,S,S
+pair,10,S,S,S,S,S,S,S,S,S,S
+pair,A,P,P,P,P,P,P,P,P,P,P
+`
@@ -0,0 +1,196 @@
+package blackjack
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+func TestChartCSVRoundTrip(t *testing.T) {
+	for _, chart := range []*Chart{ChartS17, ChartH17} {
+		var buf bytes.Buffer
+		if err := chart.WriteCSV(&buf); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadChartCSV(chart.Name, &buf)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", chart.Name, err)
+		}
+		for total, row := range chart.Hard {
+			if got.Hard[total] != row {
+				t.Errorf("%s: hard %d: want %v, got %v", chart.Name, total, row, got.Hard[total])
+			}
+		}
+		if got.Pairs[11] != chart.Pairs[11] {
+			t.Errorf("%s: aces: want %v, got %v", chart.Name, chart.Pairs[11], got.Pairs[11])
+		}
+	}
+}
+
+func TestReadChartYAML(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("name: stand\nhard:\n")
+	for total := 5; total <= 21; total++ {
+		b.WriteString("  " + itoa(total) + ": [S, S, S, S, S, S, S, S, S, S]\n")
+	}
+	b.WriteString("soft:\n")
+	for total := 13; total <= 21; total++ {
+		b.WriteString("  " + itoa(total) + ": [S, S, S, S, S, S, S, S, S, S]\n")
+	}
+	b.WriteString("pairs:\n")
+	for value := 2; value <= 10; value++ {
+		b.WriteString("  " + itoa(value) + ": [S, S, S, S, S, S, S, S, S, S]\n")
+	}
+	b.WriteString("  A: [P, P, P, P, P, P, P, P, P, Rp]\n")
+	c, err := ReadChartYAML(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if c.Name != "stand" {
+		t.Errorf("want name %q, got %q", "stand", c.Name)
+	}
+	if got := c.Pairs[11][9]; got != ActionSurrenderOrSplit {
+		t.Errorf("aces against an ace: want %q, got %q", ActionSurrenderOrSplit, got)
+	}
+}
+
+func itoa(n int) string {
+	return strconv.Itoa(n)
+}
+
+func TestReadChartInvalid(t *testing.T) {
+	tests := map[string]string{
+		"missing rows":   "hard,5,H,H,H,H,H,H,H,H,H,H\n",
+		"unknown table":  "firm,5,H,H,H,H,H,H,H,H,H,H\n",
+		"unknown action": strings.Replace(chartS17, "hard,5,H", "hard,5,X", 1),
+		"short row":      strings.Replace(chartS17, "hard,5,H,", "hard,5,", 1),
+		"bad total":      strings.Replace(chartS17, "hard,5,", "hard,five,", 1),
+	}
+	for name, data := range tests {
+		if _, err := ReadChartCSV(name, strings.NewReader(data)); err == nil {
+			t.Errorf("%s: want an error, got nil", name)
+		}
+	}
+}
+
+func TestChartLookup(t *testing.T) {
+	tests := []struct {
+		hand   string
+		dealer string
+		pair   bool
+		want   Action
+	}{
+		{"10S 6H", "10D", true, ActionSurrender},
+		{"5S 6H", "6D", true, ActionDouble},
+		{"AS 7H", "9D", true, ActionHit},
+		{"AS 7H", "3D", true, ActionDoubleOrStand},
+		{"8S 8H", "10D", true, ActionSplit},
+		{"8S 8H", "10D", false, ActionSurrender},
+		{"AS AH", "6D", true, ActionSplit},
+		{"AS AH", "6D", false, ActionStand},
+		{"2S 2H", "5D", false, ActionHit},
+		{"10S 9H 5D", "5D", true, ActionStand},
+	}
+	for _, tt := range tests {
+		hand, _ := deck.ParseHand(tt.hand)
+		dealer, _ := deck.ParseCard(tt.dealer)
+		if got := ChartS17.Lookup(hand, dealer, tt.pair); got != tt.want {
+			t.Errorf("Lookup(%s, %s, %t): want %q, got %q", tt.hand, tt.dealer, tt.pair, tt.want, got)
+		}
+	}
+}
+
+func TestBasicStrategy(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		hand   string
+		dealer string
+		want   Move
+	}{
+		{"double 11", Options{}, "5S 6H", "6D", MoveDouble},
+		{"hit 11 after doubling refused", Options{Double: DoubleTenToEleven}, "5S 4H 2D", "6D", MoveHit},
+		{"stand soft 18 when doubling is not allowed", Options{Double: DoubleNineToEleven}, "AS 7H", "3D", MoveStand},
+		{"surrender 16", Options{Surrender: SurrenderLate}, "10S 6H", "10D", MoveSurrender},
+		{"hit 16 without surrender", Options{}, "10S 6H", "10D", MoveHit},
+		{"split 8s", Options{Surrender: SurrenderLate}, "8S 8H", "10D", MoveSplit},
+		{"split 2s with DAS", Options{}, "2S 2H", "2D", MoveSplit},
+		{"hit 2s without DAS", Options{NoDoubleAfterSplit: true}, "2S 2H", "2D", MoveHit},
+		{"stand hard 17", Options{}, "10S 7H", "AD", MoveStand},
+		{"early surrender 16 against an ace", Options{Surrender: SurrenderEarly}, "10S 6H", "AD", MoveSurrender},
+		{"early surrender declined", Options{Surrender: SurrenderEarly}, "10S 7H", "10D", MoveStand},
+	}
+	for _, tt := range tests {
+		ai := BasicStrategy(nil, tt.opts)
+		ai.Bet(true)
+		hand, _ := deck.ParseHand(tt.hand)
+		dealer, _ := deck.ParseCard(tt.dealer)
+		move := ai.Play(hand, dealer)
+		if moveName(move) != moveName(tt.want) {
+			t.Errorf("%s: want %s, got %s", tt.name, moveName(tt.want), moveName(move))
+		}
+	}
+}
+
+func TestBasicStrategyPlaysLegally(t *testing.T) {
+	for _, opts := range []Options{
+		{Decks: 6, Hands: 2000, Seed: 1},
+		{Decks: 2, Hands: 2000, Seed: 2, StandSoft17: true, Surrender: SurrenderLate, MaxSplitHands: 2},
+		{Decks: 8, Hands: 2000, Seed: 3, Surrender: SurrenderEarly, NoDoubleAfterSplit: true, Double: DoubleTenToEleven},
+		{Decks: 6, Hands: 2000, Seed: 4, NoHoleCard: true, ResplitAces: true},
+	} {
+		game := New(opts)
+		results, err := game.Play(Seat{AI: BasicStrategy(nil, opts)}, Seat{AI: BasicStrategy(nil, opts)})
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %v", opts, err)
+			continue
+		}
+		for i, r := range results {
+			if r.Rounds != opts.Hands {
+				t.Errorf("%+v seat %d: want %d rounds, got %d", opts, i, opts.Hands, r.Rounds)
+			}
+		}
+	}
+}
+
+func TestBasicStrategyBet(t *testing.T) {
+	if got := BasicStrategy(nil, Options{}).Bet(true); got != DefaultBet {
+		t.Errorf("default: want a bet of %d, got %d", DefaultBet, got)
+	}
+	if got := BasicStrategy(nil, Options{}, FlatBet(25)).Bet(true); got != 25 {
+		t.Errorf("flat bet: want a bet of 25, got %d", got)
+	}
+}
+
+func TestBasicStrategySurrender(t *testing.T) {
+	opts := Options{Surrender: SurrenderEarly}
+	tests := []struct {
+		hand   string
+		dealer string
+		want   bool
+	}{
+		{"10S 6H", "AD", true},
+		{"10S 6H", "10D", true},
+		{"10S 7H", "10D", false},
+		{"8S 8H", "6D", false},
+	}
+	for _, tt := range tests {
+		ai := BasicStrategy(nil, opts)
+		ai.Bet(true)
+		hand, _ := deck.ParseHand(tt.hand)
+		dealer, _ := deck.ParseCard(tt.dealer)
+		if got := ai.Surrender(hand, dealer); got != tt.want {
+			t.Errorf("%s vs %s: want early surrender %v, got %v", tt.hand, tt.dealer, tt.want, got)
+		}
+	}
+}
+
+// moveName returns the name of a move's function, since funcs can't be compared.
+func moveName(m Move) string {
+	return runtime.FuncForPC(reflect.ValueOf(m).Pointer()).Name()
+}
@@ -0,0 +1,99 @@
+package blackjack
+
+import "github.com/jeremy-miller/gophercises/deck"
+
+// EventType is the kind of thing which happened at the table.
+type EventType uint8
+
+const (
+	EventShuffle   EventType = iota // the shoe was shuffled before the round
+	EventBet                        // a seat placed its bet
+	EventDeal                       // a card was dealt to a seat's hand or the dealer
+	EventInsurance                  // a seat took insurance (or even money)
+	EventMove                       // a seat or the dealer made a move
+	EventBust                       // a hand went over 21
+	EventReveal                     // the dealer turned over the hole card
+	EventSettle                     // a bet was paid out or collected
+	EventRoundOver                  // every bet has been settled and the cards cleared away
+)
+
+var eventNames = [...]string{"shuffle", "bet", "deal", "insurance", "move", "bust", "reveal", "settle", "round over"}
+
+func (t EventType) String() string {
+	if int(t) < len(eventNames) {
+		return eventNames[t]
+	}
+	return "unknown"
+}
+
+// Dealer is the seat number used in events about the dealer's hand.
+const Dealer = -1
+
+// Outcome is how a hand's bet was settled.
+type Outcome string
+
+const (
+	OutcomeWin       Outcome = "win"
+	OutcomeLoss      Outcome = "loss"
+	OutcomePush      Outcome = "push"
+	OutcomeBlackjack Outcome = "blackjack"
+	OutcomeSurrender Outcome = "surrender"
+	OutcomeInsurance Outcome = "insurance" // the insurance bet, which is settled on its own
+)
+
+// Event is something which happened during a game. Only the fields which make sense for its Type are set.
+type Event struct {
+	Type  EventType
+	Round int // rounds are numbered from 1
+	Seat  int // the seat's index in the call to Play, or Dealer
+	Hand  int // the index of the seat's hand, which is only ever more than 0 after splitting
+
+	Card    deck.Card // the card dealt or revealed
+	Hole    bool      // the card dealt is the dealer's face down hole card, so Card isn't set
+	Move    string    // the name of the move: hit, stand, double, split or surrender
+	Amount  int       // the bet or insurance placed, or the net winnings when settled
+	Outcome Outcome
+}
+
+// Observer is an optional interface an AI can implement to be told about everything which happens at
+// the table. Other observers, like a front-end or a hand history, can be added with Game.Observe.
+type Observer interface {
+	ObserveEvent(e Event)
+}
+
+// ObserverFunc adapts a function into an Observer, e.g. one which sends every event down a channel.
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) ObserveEvent(e Event) {
+	f(e)
+}
+
+// Observe adds an observer which will be told about every event in the games played from now on.
+func (g *Game) Observe(o Observer) {
+	g.eventObservers = append(g.eventObservers, o)
+}
+
+// emit sends the event to every observer.
+func emit(g *Game, e Event) {
+	e.Round = g.round
+	for _, o := range g.eventObservers {
+		o.ObserveEvent(e)
+	}
+	for _, o := range g.seatObservers {
+		o.ObserveEvent(e)
+	}
+}
+
+// position returns the seat and hand which is currently being played.
+func (g *Game) position() (seat, hand int) {
+	if g.state == stateDealerTurn {
+		return Dealer, 0
+	}
+	return g.cur, g.players[g.cur].handIdx
+}
+
+// emitMove sends the event for a move made at the current position.
+func emitMove(g *Game, move string) {
+	seat, hand := g.position()
+	emit(g, Event{Type: EventMove, Seat: seat, Hand: hand, Move: move})
+}
@@ -0,0 +1,93 @@
+package blackjack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// eventString formats the fields of an event which are set for its type.
+func eventString(e Event) string {
+	s := fmt.Sprintf("%s %d", e.Type, e.Seat)
+	if e.Hand > 0 {
+		s += fmt.Sprintf("/%d", e.Hand)
+	}
+	switch e.Type {
+	case EventDeal, EventReveal:
+		if e.Hole {
+			s += " hole"
+		} else {
+			text, _ := e.Card.MarshalText()
+			s += " " + string(text)
+		}
+	case EventMove:
+		s += " " + e.Move
+	case EventBet, EventInsurance:
+		s += fmt.Sprint(" ", e.Amount)
+	case EventSettle:
+		s += fmt.Sprint(" ", e.Outcome, " ", e.Amount)
+	}
+	return s
+}
+
+func TestEvents(t *testing.T) {
+	stack, err := deck.ParseHand("8S 10H 8D 9D 5C KC 10C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New(Options{})
+	var events []string
+	g.Observe(ObserverFunc(func(e Event) {
+		events = append(events, eventString(e))
+	}))
+	g.shoe = deck.NewShoe(1, func([]deck.Card) []deck.Card { return stack })
+	g.players = []*player{{ai: &scriptAI{bet: 10, moves: []Move{MoveSplit, MoveHit}}}}
+	if err := playRound(&g, true); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	want := []string{
+		"bet 0 10",
+		"deal 0 8S", "deal -1 10H", "deal 0 8D", "deal -1 hole",
+		"move 0 split", "deal 0 5C", // the first hand gets its second card
+		"move 0 hit", "deal 0 KC", "bust 0",
+		"deal 0/1 10C", // the second hand gets its second card once the first is done
+		"move 0/1 stand",
+		"reveal -1 9D", "move -1 stand",
+		"settle 0 loss -10", "settle 0/1 loss -10", "round over 0",
+	}
+	if strings.Join(events, ", ") != strings.Join(want, ", ") {
+		t.Errorf("want events\n%v\ngot\n%v", want, events)
+	}
+}
+
+func TestPlayReturnsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ai   *scriptAI
+	}{
+		{"illegal bet", &scriptAI{bet: 0}},
+		{"nil move", &scriptAI{bet: 1, moves: []Move{nil}}},
+	}
+	for _, tt := range tests {
+		g := New(Options{Hands: 5, Seed: 1})
+		results, err := g.Play(Seat{AI: tt.ai, Bankroll: 100})
+		if err == nil {
+			t.Errorf("%s: want an error, got nil", tt.name)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "round 1: ") {
+			t.Errorf("%s: want the error to name the round, got %q", tt.name, err)
+		}
+		if len(results) != 1 || results[0].Balance != 100 {
+			t.Errorf("%s: want the results before the error, got %+v", tt.name, results)
+		}
+	}
+	g := New(Options{Hands: 1, Seed: 1})
+	_, err := g.Play(Seat{AI: &scriptAI{bet: 1, moves: []Move{nil}}})
+	if !errors.Is(err, errNoMove) {
+		t.Errorf("want the AI's error to be wrapped, got %v", err)
+	}
+}
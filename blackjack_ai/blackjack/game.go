@@ -3,6 +3,7 @@ package blackjack
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jeremy-miller/gophercises/deck"
 )
@@ -11,38 +12,185 @@ type state uint8
 
 const (
 	stateBet state = iota
+	stateEarlySurrender
 	statePlayerTurn
 	stateDealerTurn
 	stateHandOver
 )
 
+// Surrender is the rule for when a player may give up their hand for half of their bet.
+type Surrender uint8
+
+const (
+	SurrenderNone  Surrender = iota
+	SurrenderLate            // only after the dealer has checked for blackjack
+	SurrenderEarly           // before the dealer checks for blackjack
+)
+
 type Options struct {
-	Decks           int
-	Hands           int
-	BlackjackPayout float64
+	Decks              int
+	Hands              int
+	BlackjackPayout    float64
+	Seed               int64 // seed for shuffling the deck; if 0, a seed is chosen from the current time
+	SecureShuffle      bool  // shuffle with deck.SecureShuffle, so the order can't be predicted; Seed is ignored
+	MaxSplitHands      int   // most hands a player can hold by splitting and resplitting pairs; defaults to 4
+	ResplitAces        bool  // allow split aces to be split again when dealt another ace
+	NoDoubleAfterSplit bool  // disallow doubling down on a hand which came from a split
+	Surrender          Surrender
+	NoInsurance        bool // don't offer insurance (or even money) when the dealer shows an ace
+	StandSoft17        bool // the dealer stands on soft 17 (S17); by default the dealer hits it (H17)
+	Double             DoubleRule
+	NoHoleCard         bool    // European rules: the dealer's second card is dealt after the players act, so there's no peek for blackjack
+	Penetration        float64 // fraction of the shoe dealt before reshuffling; defaults to 2/3
+	MinBet             int     // defaults to 1
+	MaxBet             int     // 0 means there is no maximum
 }
 
+// Validate returns an error if the options can't be played. Zero values are valid, as New fills in the
+// defaults for them.
+func (opts Options) Validate() error {
+	switch {
+	case opts.Decks < 0 || opts.Hands < 0 || opts.MaxSplitHands < 0:
+		return errors.New("the numbers of decks, hands and split hands can't be negative")
+	case opts.BlackjackPayout < 0:
+		return fmt.Errorf("the blackjack payout of %g can't be negative", opts.BlackjackPayout)
+	case opts.Penetration < 0 || opts.Penetration > 1:
+		return fmt.Errorf("the penetration of %g must be between 0 and 1", opts.Penetration)
+	case opts.Surrender > SurrenderEarly:
+		return fmt.Errorf("unknown surrender rule %d", opts.Surrender)
+	case opts.Double > DoubleTenToEleven:
+		return fmt.Errorf("unknown double rule %d", opts.Double)
+	case opts.MinBet < 0:
+		return fmt.Errorf("the minimum bet of %d must be at least 1", opts.MinBet)
+	case opts.MaxBet < 0, opts.MaxBet > 0 && opts.MaxBet < opts.MinBet:
+		return fmt.Errorf("the maximum bet of %d is below the minimum bet", opts.MaxBet)
+	}
+	return nil
+}
+
+// DoubleRule is the rule for which two card hands a player may double down on.
+type DoubleRule uint8
+
+const (
+	DoubleAny          DoubleRule = iota
+	DoubleNineToEleven            // only totals of 9, 10 or 11
+	DoubleTenToEleven             // only totals of 10 or 11
+)
+
 type Game struct {
 	// unexported fields
 	numDecks        int
 	numHands        int
 	blackjackPayout float64
+	seed            int64
+	secureShuffle   bool
+
+	maxSplitHands      int
+	resplitAces        bool
+	noDoubleAfterSplit bool
+	surrender          Surrender
+	noInsurance        bool
+	standSoft17        bool
+	double             DoubleRule
+	noHoleCard         bool
+	penetration        float64
+	minBet, maxBet     int
 
 	state state
-	deck  []deck.Card
+	shoe  *deck.Shoe
+	err   error // from validating the options
+
+	players []*player
+	cur     int // index of the player whose turn it is
 
-	player    []deck.Card
-	playerBet int
-	balance   int
+	dealer       []deck.Card
+	dealerAI     AI
+	holeRevealed bool
+
+	observers      []CardObserver
+	round          int
+	eventObservers []Observer // added with Observe
+	seatObservers  []Observer // the AIs of the seats being played
+}
 
-	dealer   []deck.Card
-	dealerAI AI
+// Seat is a place at the table, played by an AI with its own bankroll.
+type Seat struct {
+	AI       AI
+	Bankroll int  // the seat's balance before the first hand
+	Stop     Stop // when the seat leaves the table; by default it plays every round
 }
 
+// Stop is when a seat leaves the table before the game's last round. Each condition is checked before
+// every round.
+type Stop struct {
+	Broke  bool // leave once the balance can't cover the table minimum or the seat's next bet
+	Goal   int  // leave once the balance reaches the goal, if positive
+	Rounds int  // leave after playing this many rounds, if positive
+}
+
+// StopReason is why a seat left the table.
+type StopReason string
+
+const (
+	StopNone   StopReason = ""
+	StopBroke  StopReason = "broke"
+	StopGoal   StopReason = "goal"
+	StopRounds StopReason = "rounds"
+	StopLeft   StopReason = "left" // the AI bet LeaveTable
+)
+
+// Result is the outcome of a game for one seat. Hand counts include every hand created by splitting.
+type Result struct {
+	Balance    int // the seat's bankroll after the last hand
+	Rounds     int
+	Hands      int
+	Wins       int
+	Losses     int
+	Pushes     int
+	Blackjacks int
+	Surrenders int
+	Wagered    int        // total of every bet placed, including doubles, splits and insurance
+	Low        int        // the seat's lowest balance after any round, for working out the risk of ruin
+	Stopped    StopReason // why the seat left the table early, if it did
+	// SumSquares is the sum of the square of each round's net winnings, for working out the variance.
+	SumSquares float64
+}
+
+// player is the state of a seat during a game.
+type player struct {
+	ai        AI
+	hands     []hand
+	handIdx   int // index of the hand currently being played
+	bet       int
+	insurance int
+	result    Result
+	stop      Stop
+}
+
+// left returns true if the player has left the table.
+func (p *player) left() bool {
+	return p.result.Stopped != StopNone
+}
+
+// checkStop makes the player leave the table if any of their stop conditions has been met.
+func checkStop(g *Game, p *player) {
+	switch r := &p.result; {
+	case r.Stopped != StopNone:
+	case p.stop.Broke && r.Balance < g.minBet:
+		r.Stopped = StopBroke
+	case p.stop.Goal > 0 && r.Balance >= p.stop.Goal:
+		r.Stopped = StopGoal
+	case p.stop.Rounds > 0 && r.Rounds >= p.stop.Rounds:
+		r.Stopped = StopRounds
+	}
+}
+
+// New returns a game with the given rules. If the options aren't valid, Play returns the error from
+// Options.Validate without playing any rounds.
 func New(opts Options) Game {
 	g := Game{
-		dealerAI: dealerAI{},
-		balance:  0,
+		dealerAI: dealerAI{hitSoft17: !opts.StandSoft17},
+		err:      opts.Validate(),
 	}
 	if opts.Decks == 0 {
 		opts.Decks = 3
@@ -53,67 +201,358 @@ func New(opts Options) Game {
 	if opts.BlackjackPayout == 0 {
 		opts.BlackjackPayout = 1.5
 	}
+	if opts.MaxSplitHands == 0 {
+		opts.MaxSplitHands = 4
+	}
+	if opts.Penetration == 0 {
+		opts.Penetration = 2.0 / 3 // reshuffle when we're down to 1/3 of total cards left in the shoe
+	}
+	if opts.MinBet == 0 {
+		opts.MinBet = 1
+	}
 	g.numDecks = opts.Decks
 	g.numHands = opts.Hands
 	g.blackjackPayout = opts.BlackjackPayout
+	g.seed = opts.Seed
+	g.secureShuffle = opts.SecureShuffle
+	if g.secureShuffle {
+		g.seed = 0
+	}
+	g.maxSplitHands = opts.MaxSplitHands
+	g.resplitAces = opts.ResplitAces
+	g.noDoubleAfterSplit = opts.NoDoubleAfterSplit
+	g.surrender = opts.Surrender
+	g.noInsurance = opts.NoInsurance
+	g.standSoft17 = opts.StandSoft17
+	g.double = opts.Double
+	g.noHoleCard = opts.NoHoleCard
+	g.penetration = opts.Penetration
+	g.minBet = opts.MinBet
+	g.maxBet = opts.MaxBet
 	return g
 }
 
-func (g *Game) Play(ai AI) int {
-	g.deck = nil
-	min := 52 * g.numDecks / 3 // reshuffle when we're down to 1/3 of total cards left in deck
-	for i := 0; i < g.numHands; i++ {
-		shuffled := false
-		if len(g.deck) < min {
-			g.deck = deck.New(deck.Deck(g.numDecks), deck.Shuffle)
+// Seed returns the seed used to shuffle the deck. Passing it back in Options.Seed replays the
+// exact same sequence of shuffles, and therefore the same hands given the same decisions. It's 0 for a
+// game with a secure shuffle, which can't be replayed.
+func (g *Game) Seed() int64 {
+	return g.seed
+}
+
+// Options returns the game's rules, with the defaults filled in for any which weren't set.
+func (g *Game) Options() Options {
+	return Options{
+		Decks:              g.numDecks,
+		Hands:              g.numHands,
+		BlackjackPayout:    g.blackjackPayout,
+		Seed:               g.seed,
+		SecureShuffle:      g.secureShuffle,
+		MaxSplitHands:      g.maxSplitHands,
+		ResplitAces:        g.resplitAces,
+		NoDoubleAfterSplit: g.noDoubleAfterSplit,
+		Surrender:          g.surrender,
+		NoInsurance:        g.noInsurance,
+		StandSoft17:        g.standSoft17,
+		Double:             g.double,
+		NoHoleCard:         g.noHoleCard,
+		Penetration:        g.penetration,
+		MinBet:             g.minBet,
+		MaxBet:             g.maxBet,
+	}
+}
+
+// Play plays the game's hands with every seat dealt from the same shoe, in seat order, and returns
+// each seat's result in the same order as the seats. If an AI makes an illegal move or bet the game
+// stops, returning the results of the rounds played before it along with the error.
+func (g *Game) Play(seats ...Seat) ([]Result, error) {
+	return g.PlayShoe(g.NewShoe(), seats...)
+}
+
+// NewShoe returns the shoe Play deals from, built from the options: it's shuffled with the seed, or securely
+// if the options ask for it. Keeping the shoe and passing it to PlayShoe gives access to its cards, e.g. to
+// commit to the order of each shoe with deck.Commit when the game is shuffled.
+func (g *Game) NewShoe() *deck.Shoe {
+	if g.secureShuffle {
+		return deck.NewShoe(g.penetration, deck.Deck(g.numDecks), deck.SecureShuffle)
+	}
+	if g.seed == 0 {
+		g.seed = time.Now().UnixNano()
+	}
+	return deck.NewShoe(g.penetration, deck.Deck(g.numDecks), deck.Seed(g.seed))
+}
+
+// PlayShoe is like Play, but deals from the given shoe rather than a new one built from the options.
+// The shoe is played from its current position, which is how a recorded round can be played again.
+func (g *Game) PlayShoe(shoe *deck.Shoe, seats ...Seat) ([]Result, error) {
+	g.shoe = shoe
+	g.players = make([]*player, len(seats))
+	g.observers = nil
+	g.seatObservers = nil
+	for i, s := range seats {
+		g.players[i] = &player{
+			ai:     s.AI,
+			result: Result{Balance: s.Bankroll, Low: s.Bankroll},
+			stop:   s.Stop,
+		}
+		if o, ok := s.AI.(CardObserver); ok {
+			g.observers = append(g.observers, o)
+		}
+		if o, ok := s.AI.(Observer); ok {
+			g.seatObservers = append(g.seatObservers, o)
+		}
+	}
+	err := g.err
+	shuffled := shoe.Remaining() == shoe.Size()
+	for g.round = 1; err == nil && g.round <= g.numHands; g.round++ {
+		seated := false
+		for _, p := range g.players {
+			checkStop(g, p)
+			seated = seated || !p.left()
+		}
+		if !seated {
+			break
+		}
+		if g.shoe.NeedsShuffle() {
+			g.shoe.Shuffle()
 			shuffled = true
 		}
-		bet(g, ai, shuffled)
-		deal(g)
-		if Blackjack(g.dealer...) {
-			endHand(g, ai)
+		if shuffled {
+			emit(g, Event{Type: EventShuffle})
+		}
+		if err = playRound(g, shuffled); err != nil {
+			err = fmt.Errorf("round %d: %w", g.round, err)
+			break
+		}
+		shuffled = false
+	}
+	results := make([]Result, len(g.players))
+	for i, p := range g.players {
+		results[i] = p.result
+	}
+	return results, err
+}
+
+// playRound plays a single round of blackjack for every seat, from the bets through to settling them.
+func playRound(g *Game, shuffled bool) error {
+	for i, p := range g.players {
+		if p.left() {
+			continue
+		}
+		if err := bet(g, p, shuffled); err != nil {
+			return err
+		}
+		if p.left() {
 			continue
 		}
+		emit(g, Event{Type: EventBet, Seat: i, Amount: p.bet})
+	}
+	if err := deal(g); err != nil {
+		return err
+	}
+	up := g.dealer[0]
+	if up.Rank == deck.Ace && !g.noInsurance {
+		for i, p := range g.players {
+			if !p.left() && insure(p) {
+				emit(g, Event{Type: EventInsurance, Seat: i, Amount: p.insurance})
+			}
+		}
+	}
+	if g.surrender == SurrenderEarly && (up.Rank == deck.Ace || Score(up) == 10) {
+		for i, p := range g.players {
+			if p.left() {
+				continue
+			}
+			g.cur = i
+			earlySurrender(g)
+		}
+	}
+	if Blackjack(g.dealer...) {
+		return endHand(g)
+	}
+	for i, p := range g.players {
+		if p.left() {
+			continue
+		}
+		g.cur = i
+		if p.hands[0].surrendered || Blackjack(p.hands[0].cards...) {
+			continue
+		}
+		g.state = statePlayerTurn
 		for g.state == statePlayerTurn {
-			hand := make([]deck.Card, len(g.player))
-			copy(hand, g.player)
-			move := ai.Play(hand, g.dealer[0]) // only pass in first card of dealer's hand
+			cards := p.hands[p.handIdx].cards
+			hand := make([]deck.Card, len(cards))
+			copy(hand, cards)
+			move := p.ai.Play(hand, g.dealer[0]) // only pass in first card of dealer's hand
+			if move == nil {
+				return errNoMove
+			}
 			err := move(g)
-			switch err {
-			case errBust:
-				_ := MoveStand(g) // ignore error since it always returns nil
-			case nil:
-				// noop
-			default:
-				panic(err)
+			if err == errBust {
+				err = stand(g)
+			}
+			if err != nil {
+				return err
 			}
 		}
-		for g.state == stateDealerTurn {
-			hand := make([]deck.Card, len(g.dealer))
-			copy(hand, g.dealer)
-			move := g.dealerAI.Play(hand, g.dealer[0])
-			move(g)
+	}
+	if err := revealHole(g); err != nil {
+		return err
+	}
+	g.state = stateDealerTurn
+	if !live(g) { // the dealer has nothing to play against
+		g.state = stateHandOver
+	}
+	for g.state == stateDealerTurn {
+		hand := make([]deck.Card, len(g.dealer))
+		copy(hand, g.dealer)
+		move := g.dealerAI.Play(hand, g.dealer[0])
+		if err := move(g); err != nil && err != errBust {
+			return err
 		}
-		endHand(g, ai)
 	}
-	return g.balance
+	return endHand(g)
 }
 
-func bet(g *Game, ai AI, shuffled bool) {
-	g.playerBet = ai.Bet(shuffled)
+func bet(g *Game, p *player, shuffled bool) error {
+	if o, ok := p.ai.(BankrollObserver); ok {
+		o.ObserveBankroll(p.result.Balance)
+	}
+	p.bet = p.ai.Bet(shuffled)
+	p.insurance = 0
+	if p.bet == LeaveTable {
+		p.result.Stopped = StopLeft
+		return nil
+	}
+	if p.bet <= 0 {
+		return fmt.Errorf("bet of %d isn't positive", p.bet)
+	}
+	if p.bet < g.minBet || (g.maxBet > 0 && p.bet > g.maxBet) {
+		return fmt.Errorf("bet of %d is outside the table limits", p.bet)
+	}
+	if p.stop.Broke && p.bet > p.result.Balance {
+		p.result.Stopped = StopBroke
+	}
+	return nil
 }
 
-func deal(g *Game) {
-	g.player = make([]deck.Card, 0, 5) // likely won't have more than 5 cards in hand in a game
+// insure offers the player insurance, a side bet of half their bet which pays 2 to 1 if the dealer
+// has blackjack, and returns true if they took it. Taking insurance on a blackjack is the same as
+// taking even money.
+func insure(p *player) bool {
+	if p.bet/2 == 0 {
+		return false
+	}
+	hand := make([]deck.Card, len(p.hands[0].cards))
+	copy(hand, p.hands[0].cards)
+	if p.ai.Insurance(hand) {
+		p.insurance = p.bet / 2
+		return true
+	}
+	return false
+}
+
+// earlySurrender offers the current player the chance to surrender before the dealer checks for blackjack.
+// If they decline, they play their hand as usual once the dealer has checked.
+func earlySurrender(g *Game) {
+	p := g.players[g.cur]
+	g.state = stateEarlySurrender
+	hand := make([]deck.Card, len(p.hands[0].cards))
+	copy(hand, p.hands[0].cards)
+	if p.ai.Surrender(hand, g.dealer[0]) {
+		emitMove(g, "surrender")
+		p.hands[0].surrendered = true
+	}
+}
+
+// live returns true if any player has a hand which still needs the dealer to play out their hand.
+func live(g *Game) bool {
+	for _, p := range g.players {
+		for _, h := range p.hands {
+			natural := Blackjack(h.cards...) && !h.split
+			if !h.surrendered && !natural && Score(h.cards...) <= 21 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deal deals two cards to every player and the dealer, one at a time starting with the first seat.
+func deal(g *Game) error {
+	for _, p := range g.players {
+		if p.left() {
+			continue
+		}
+		p.hands = []hand{{
+			cards: make([]deck.Card, 0, 5), // likely won't have more than 5 cards in hand in a game
+			bet:   p.bet,
+		}}
+		p.handIdx = 0
+	}
 	g.dealer = make([]deck.Card, 0, 5)
-	var card deck.Card
+	g.holeRevealed = false
 	for i := 0; i < 2; i++ {
-		card, g.deck = draw(g.deck)
-		g.player = append(g.player, card)
-		card, g.deck = draw(g.deck)
+		for seat, p := range g.players {
+			if p.left() {
+				continue
+			}
+			card, err := g.shoe.Draw()
+			if err != nil {
+				return err
+			}
+			p.hands[0].cards = append(p.hands[0].cards, card)
+			emit(g, Event{Type: EventDeal, Seat: seat, Card: card})
+		}
+		if i == 1 && g.noHoleCard {
+			break // the dealer's second card is dealt after the players act
+		}
+		card, err := g.shoe.Draw()
+		if err != nil {
+			return err
+		}
 		g.dealer = append(g.dealer, card)
+		if i == 0 {
+			emit(g, Event{Type: EventDeal, Seat: Dealer, Card: card})
+		} else {
+			emit(g, Event{Type: EventDeal, Seat: Dealer, Hole: true})
+		}
+	}
+	for _, p := range g.players {
+		if !p.left() {
+			reveal(g, p.hands[0].cards...)
+		}
+	}
+	reveal(g, g.dealer[0]) // the dealer's second card is the hole card, which stays face down
+	return nil
+}
+
+// reveal shows cards which were just turned face up to every observer at the table.
+func reveal(g *Game, cards ...deck.Card) {
+	for _, o := range g.observers {
+		for _, c := range cards {
+			o.ObserveCard(c)
+		}
 	}
-	g.state = statePlayerTurn
+}
+
+// revealHole turns over the dealer's hole card, or deals the dealer's second card when playing without one.
+func revealHole(g *Game) error {
+	if g.holeRevealed {
+		return nil
+	}
+	g.holeRevealed = true
+	if len(g.dealer) < 2 {
+		card, err := g.shoe.Draw()
+		if err != nil {
+			return err
+		}
+		g.dealer = append(g.dealer, card)
+		emit(g, Event{Type: EventDeal, Seat: Dealer, Card: card})
+	} else {
+		emit(g, Event{Type: EventReveal, Seat: Dealer, Card: g.dealer[1]})
+	}
+	reveal(g, g.dealer[1])
+	return nil
 }
 
 // Score will take in a hand of cards and return the best blackjack score possible with that hand.
@@ -159,76 +598,339 @@ func Blackjack(hand ...deck.Card) bool {
 }
 
 var (
-	errBust = errors.New("hand score exceeded 21")
+	errBust   = errors.New("hand score exceeded 21")
+	errNoMove = errors.New("AI returned a nil move")
+	errNoTurn = errors.New("it isn't currently any player's turn")
 )
 
 type Move func(*Game) error
 
+// hand is one of the player's hands; there is more than one after splitting.
+type hand struct {
+	cards []deck.Card
+	bet   int
+	split bool // a two card 21 on a split hand isn't a blackjack
+	aces  bool // split aces only receive one card each
+
+	surrendered bool
+}
+
 func MoveHit(g *Game) error {
-	hand := g.currentHand()
-	var card deck.Card
-	card, g.deck = draw(g.deck)
+	if err := checkHit(g); err != nil {
+		return err
+	}
+	emitMove(g, "hit")
+	return hit(g)
+}
+
+func checkHit(g *Game) error {
+	if _, err := g.currentHand(); err != nil {
+		return err
+	}
+	if g.state == statePlayerTurn && g.current().aces {
+		return errors.New("can't hit split aces")
+	}
+	return nil
+}
+
+func hit(g *Game) error {
+	hand, err := g.currentHand()
+	if err != nil {
+		return err
+	}
+	card, err := g.shoe.Draw()
+	if err != nil {
+		return err
+	}
 	*hand = append(*hand, card)
+	seat, idx := g.position()
+	emit(g, Event{Type: EventDeal, Seat: seat, Hand: idx, Card: card})
+	reveal(g, card)
 	if Score(*hand...) > 21 {
+		emit(g, Event{Type: EventBust, Seat: seat, Hand: idx})
 		return errBust
 	}
 	return nil
 }
 
-func (g *Game) currentHand() *[]deck.Card {
+// currentHand returns the cards of the hand being played, which is the dealer's during the dealer's turn.
+func (g *Game) currentHand() (*[]deck.Card, error) {
 	switch g.state {
 	case statePlayerTurn:
-		return &g.player
+		return &g.current().cards, nil
 	case stateDealerTurn:
-		return &g.dealer
-	default: // shouldn't ever happen, if so, there's a bug
-		panic("it isn't currently any player's turn.")
+		return &g.dealer, nil
+	default:
+		return nil, errNoTurn
 	}
 }
 
-func draw(cards []deck.Card) (deck.Card, []deck.Card) {
-	return cards[0], cards[1:]
+// current returns the hand currently being played by the current player.
+func (g *Game) current() *hand {
+	p := g.players[g.cur]
+	return &p.hands[p.handIdx]
 }
 
 func MoveDouble(g *Game) error {
-	if len(g.player) != 2 {
+	if err := checkDouble(g); err != nil {
+		return err
+	}
+	emitMove(g, "double")
+	g.current().bet *= 2
+	if err := hit(g); err != nil && err != errBust { // on errBust we'll have to stand anyways
+		return err
+	}
+	return stand(g)
+}
+
+func checkDouble(g *Game) error {
+	if g.state != statePlayerTurn {
+		return errNoTurn
+	}
+	h := g.current()
+	if len(h.cards) != 2 {
 		return errors.New("can only double on a hand with 2 cards")
 	}
-	g.playerBet *= 2
-	_ := MoveHit(g) // ignore error since only error can be errBust, so we'll have to stand anyways
-	return MoveStand(g)
+	if h.split && (g.noDoubleAfterSplit || h.aces) {
+		return errors.New("can't double after splitting")
+	}
+	score := Score(h.cards...)
+	switch {
+	case g.double == DoubleNineToEleven && (score < 9 || score > 11):
+		return errors.New("can only double on 9, 10 or 11")
+	case g.double == DoubleTenToEleven && (score < 10 || score > 11):
+		return errors.New("can only double on 10 or 11")
+	}
+	return nil
+}
+
+// MoveSplit splits a pair into two hands, each with the original bet. The first hand is dealt its
+// second card and played out before the next one is.
+func MoveSplit(g *Game) error {
+	if err := checkSplit(g); err != nil {
+		return err
+	}
+	emitMove(g, "split")
+	p := g.players[g.cur]
+	h := g.current()
+	h.split = true
+	h.aces = h.cards[0].Rank == deck.Ace
+	next := hand{
+		cards: []deck.Card{h.cards[1]},
+		bet:   h.bet,
+		split: true,
+		aces:  h.aces,
+	}
+	h.cards = h.cards[:1]
+	p.hands = append(p.hands, hand{})
+	copy(p.hands[p.handIdx+2:], p.hands[p.handIdx+1:])
+	p.hands[p.handIdx+1] = next
+	return dealSplit(g)
+}
+
+func checkSplit(g *Game) error {
+	if g.state != statePlayerTurn {
+		return errNoTurn
+	}
+	h := g.current()
+	if len(h.cards) != 2 || h.cards[0].Rank != h.cards[1].Rank {
+		return errors.New("can only split a hand with 2 cards of the same rank")
+	}
+	if len(g.players[g.cur].hands) >= g.maxSplitHands {
+		return fmt.Errorf("can't split into more than %d hands", g.maxSplitHands)
+	}
+	if h.aces && !g.resplitAces {
+		return errors.New("can't resplit aces")
+	}
+	return nil
+}
+
+// dealSplit deals the second card to the current hand, which came from a split. Split aces are done
+// once they have it, unless they can be split again.
+func dealSplit(g *Game) error {
+	if err := hit(g); err != nil {
+		return err
+	}
+	h := g.current()
+	if h.aces && !(g.resplitAces && h.cards[1].Rank == deck.Ace && len(g.players[g.cur].hands) < g.maxSplitHands) {
+		return stand(g)
+	}
+	return nil
+}
+
+// MoveSurrender gives up the hand for half of its bet. It is only allowed as the first decision on a hand
+// which hasn't been split, and only when the table's Surrender rule allows it. Early surrender is offered
+// separately, through AI.Surrender.
+func MoveSurrender(g *Game) error {
+	if err := checkSurrender(g); err != nil {
+		return err
+	}
+	emitMove(g, "surrender")
+	g.current().surrendered = true
+	return stand(g)
+}
+
+func checkSurrender(g *Game) error {
+	if g.state != statePlayerTurn {
+		return errNoTurn
+	}
+	if g.surrender == SurrenderNone {
+		return errors.New("surrender isn't allowed")
+	}
+	if len(g.players[g.cur].hands) != 1 || len(g.current().cards) != 2 {
+		return errors.New("can only surrender on the first 2 cards of a hand")
+	}
+	return nil
 }
 
 func MoveStand(g *Game) error {
+	if _, err := g.currentHand(); err != nil {
+		return err
+	}
+	emitMove(g, "stand")
+	return stand(g)
+}
+
+// moves maps the name of each move, as used in events, to the move.
+var moves = map[string]Move{
+	"hit":       MoveHit,
+	"stand":     MoveStand,
+	"double":    MoveDouble,
+	"split":     MoveSplit,
+	"surrender": MoveSurrender,
+}
+
+// MoveByName returns the move with the given name: hit, stand, double, split or surrender.
+func MoveByName(name string) (Move, bool) {
+	m, ok := moves[name]
+	return m, ok
+}
+
+// LegalMoves returns the names of the moves the current player may make, in the order hit, stand, double,
+// split and surrender. It's meant to be called by a front-end while its AI is being asked to Play, and
+// returns nil when it isn't a player's turn, including while early surrender is being offered.
+func (g *Game) LegalMoves() []string {
+	if g.state != statePlayerTurn {
+		return nil
+	}
+	var legal []string
+	if checkHit(g) == nil {
+		legal = append(legal, "hit")
+	}
+	legal = append(legal, "stand")
+	if checkDouble(g) == nil {
+		legal = append(legal, "double")
+	}
+	if checkSplit(g) == nil {
+		legal = append(legal, "split")
+	}
+	if checkSurrender(g) == nil {
+		legal = append(legal, "surrender")
+	}
+	return legal
+}
+
+// stand finishes the current hand, moving on to the player's next split hand if they have one.
+func stand(g *Game) error {
+	if p := g.players[g.cur]; g.state == statePlayerTurn && p.handIdx+1 < len(p.hands) {
+		p.handIdx++
+		return dealSplit(g)
+	}
 	g.state++
 	return nil
 }
 
-func endHand(g *Game, ai AI) {
-	pScore, dScore := Score(g.player...), Score(g.dealer...)
-	pBlackjack, dBlackjack := Blackjack(g.player...), Blackjack(g.dealer...)
-	winnings := g.playerBet
-	switch {
-	case pBlackjack && dBlackjack:
-		winnings = 0
-	case dBlackjack:
-		winnings = -winnings
-	case pBlackjack:
-		winnings = int(float64(winnings) * g.blackjackPayout)
-	case pScore > 21:
-		winnings = -winnings
-	case dScore > 21:
-		// win
-	case pScore > dScore:
-		// win
-	case dScore > pScore:
-		winnings = -winnings
-	case pScore == dScore:
-		winnings = 0
-	}
-	g.balance += winnings
-	fmt.Println()
-	ai.Results([][]deck.Card{g.player}, g.dealer)
-	g.player = nil // clear out hands
+func endHand(g *Game) error {
+	if err := revealHole(g); err != nil {
+		return err
+	}
+	dScore, dBlackjack := Score(g.dealer...), Blackjack(g.dealer...)
+	for i, p := range g.players {
+		if !p.left() {
+			settle(g, p, i, dScore, dBlackjack)
+		}
+	}
+	for _, p := range g.players {
+		if p.left() {
+			continue
+		}
+		hands := make([][]deck.Card, len(p.hands))
+		for i, h := range p.hands {
+			hands[i] = h.cards
+			g.shoe.Discard(h.cards...)
+		}
+		p.ai.Results(hands, g.dealer)
+		p.hands = nil // clear out hands
+	}
+	g.shoe.Discard(g.dealer...)
 	g.dealer = nil
+	g.state = stateHandOver
+	emit(g, Event{Type: EventRoundOver})
+	return nil
+}
+
+// settle pays out or collects each of a player's bets against the dealer's final hand.
+func settle(g *Game, p *player, seat int, dScore int, dBlackjack bool) {
+	r := &p.result
+	r.Rounds++
+	start := r.Balance
+	for i, h := range p.hands {
+		pScore, pBlackjack := Score(h.cards...), Blackjack(h.cards...) && !h.split
+		winnings := h.bet
+		outcome := OutcomeWin
+		switch {
+		case h.surrendered:
+			winnings = -(winnings - winnings/2) // odd bets round the half kept by the player down
+			outcome = OutcomeSurrender
+			r.Surrenders++
+		case pBlackjack && dBlackjack:
+			winnings = 0
+		case dBlackjack:
+			winnings = -winnings
+		case pBlackjack:
+			winnings = int(float64(winnings) * g.blackjackPayout)
+			outcome = OutcomeBlackjack
+			r.Blackjacks++
+		case pScore > 21:
+			winnings = -winnings
+		case dScore > 21:
+			// win
+		case pScore > dScore:
+			// win
+		case dScore > pScore:
+			winnings = -winnings
+		case pScore == dScore:
+			winnings = 0
+		}
+		switch {
+		case winnings > 0:
+			r.Wins++
+		case winnings < 0:
+			r.Losses++
+			if outcome == OutcomeWin {
+				outcome = OutcomeLoss
+			}
+		default:
+			r.Pushes++
+			outcome = OutcomePush
+		}
+		emit(g, Event{Type: EventSettle, Seat: seat, Hand: i, Amount: winnings, Outcome: outcome})
+		r.Hands++
+		r.Wagered += h.bet
+		r.Balance += winnings
+	}
+	if p.insurance > 0 {
+		r.Wagered += p.insurance
+		winnings := -p.insurance
+		if dBlackjack {
+			winnings = 2 * p.insurance
+		}
+		r.Balance += winnings
+		emit(g, Event{Type: EventSettle, Seat: seat, Amount: winnings, Outcome: OutcomeInsurance})
+	}
+	net := float64(r.Balance - start)
+	r.SumSquares += net * net
+	if r.Balance < r.Low {
+		r.Low = r.Balance
+	}
 }
@@ -0,0 +1,434 @@
+package blackjack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// scriptAI bets a fixed amount and plays a fixed list of moves.
+type scriptAI struct {
+	bet       int
+	moves     []Move
+	insurance bool
+	surrender bool
+	hands     [][]deck.Card
+
+	insuranceOffers, surrenderOffers int
+}
+
+func (ai *scriptAI) Bet(shuffled bool) int {
+	return ai.bet
+}
+
+func (ai *scriptAI) Play(hand []deck.Card, dealer deck.Card) Move {
+	if len(ai.moves) == 0 {
+		return MoveStand
+	}
+	move := ai.moves[0]
+	ai.moves = ai.moves[1:]
+	return move
+}
+
+func (ai *scriptAI) Insurance(hand []deck.Card) bool {
+	ai.insuranceOffers++
+	return ai.insurance
+}
+
+func (ai *scriptAI) Surrender(hand []deck.Card, dealer deck.Card) bool {
+	ai.surrenderOffers++
+	return ai.surrender
+}
+
+func (ai *scriptAI) Results(hands [][]deck.Card, dealer []deck.Card) {
+	ai.hands = hands
+}
+
+// playStacked plays a single round dealt from the given cards, in the order player, dealer, player,
+// dealer (hole card), then any hits, and returns the player's winnings.
+func playStacked(t *testing.T, opts Options, cards string, ai *scriptAI) (int, error) {
+	results, err := playStackedSeats(t, opts, cards, ai)
+	return results[0].Balance, err
+}
+
+func playStackedSeats(t *testing.T, opts Options, cards string, ais ...*scriptAI) ([]Result, error) {
+	stack, err := deck.ParseHand(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New(opts)
+	g.shoe = deck.NewShoe(1, func([]deck.Card) []deck.Card { return stack })
+	for _, ai := range ais {
+		g.players = append(g.players, &player{ai: ai})
+	}
+	err = playRound(&g, true)
+	results := make([]Result, len(g.players))
+	for i, p := range g.players {
+		results[i] = p.result
+	}
+	return results, err
+}
+
+func TestPlayRound(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		cards string
+		ai    scriptAI
+		want  int
+	}{
+		{"stand and win", Options{}, "10S 10H QS 7D", scriptAI{bet: 10}, 10},
+		{"dealer hits hard 16", Options{}, "10S 10H QS 6D 5C", scriptAI{bet: 10}, -10},
+		{"dealer hits soft 17", Options{}, "10S AH 8S 6D 2C", scriptAI{bet: 10}, -10},
+		{"dealer stands on soft 17", Options{StandSoft17: true}, "10S AH 8S 6D 2C", scriptAI{bet: 10}, 10},
+		{"blackjack pays 3:2", Options{}, "AS 10H KS 7D", scriptAI{bet: 10}, 15},
+		{"blackjack pays 6:5", Options{BlackjackPayout: 1.2}, "AS 10H KS 7D", scriptAI{bet: 10}, 12},
+		{"bust", Options{}, "10S 10H 6S 7D KC", scriptAI{bet: 10, moves: []Move{MoveHit}}, -10},
+		{"double", Options{}, "6S 10H 5S 7D KC", scriptAI{bet: 10, moves: []Move{MoveDouble}}, 20},
+		{"split", Options{}, "8S 10H 8D 9D 3C KC 10C", scriptAI{bet: 10, moves: []Move{MoveSplit, MoveHit}}, 0},
+		{"split aces get one card", Options{}, "AS 10H AD 9D 9C KC", scriptAI{bet: 10, moves: []Move{MoveSplit}}, 20},
+		{"split 21 isn't blackjack", Options{}, "AS 10H AD 9D KC 9C", scriptAI{bet: 10, moves: []Move{MoveSplit}}, 20},
+		{"double after split", Options{}, "8S 10H 8D 7D 3C 10C 10D", scriptAI{bet: 10, moves: []Move{MoveSplit, MoveDouble}}, 30},
+		{"late surrender", Options{Surrender: SurrenderLate}, "10S 10H 6S 7D", scriptAI{bet: 10, moves: []Move{MoveSurrender}}, -5},
+		{"late surrender after peek", Options{Surrender: SurrenderLate}, "10S AH 6S KD", scriptAI{bet: 10, moves: []Move{MoveSurrender}}, -10},
+		{"early surrender before peek", Options{Surrender: SurrenderEarly}, "10S AH 6S KD", scriptAI{bet: 10, surrender: true}, -5},
+		{"early surrender declined", Options{Surrender: SurrenderEarly}, "10S AH QS 8D", scriptAI{bet: 10}, 10},
+		{"insurance pays 2:1", Options{}, "10S AH 9S KD", scriptAI{bet: 10, insurance: true}, 0},
+		{"insurance lost", Options{}, "10S AH 9S 8D", scriptAI{bet: 10, insurance: true}, -5},
+		{"even money", Options{}, "AS AH KS KD", scriptAI{bet: 10, insurance: true}, 10},
+		{"no hole card loses doubles to blackjack", Options{NoHoleCard: true}, "6S AH 5S 9C KD", scriptAI{bet: 10, moves: []Move{MoveDouble}}, -20},
+	}
+	for _, tt := range tests {
+		ai := tt.ai
+		got, err := playStacked(t, tt.opts, tt.cards, &ai)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: want winnings %d, got %d (hands %v)", tt.name, tt.want, got, ai.hands)
+		}
+	}
+}
+
+func TestOffers(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		cards     string
+		insurance int
+		surrender int
+	}{
+		{"insurance against an ace", Options{}, "10S AH 9S 8D", 1, 0},
+		{"no insurance against a ten", Options{}, "10S KH 9S 8D", 0, 0},
+		{"insurance turned off", Options{NoInsurance: true}, "10S AH 9S 8D", 0, 0},
+		{"even money on blackjack", Options{}, "AS AH KS 8D", 1, 0},
+		{"early surrender against an ace", Options{Surrender: SurrenderEarly}, "10S AH 6S 8D", 1, 1},
+		{"early surrender against a ten", Options{Surrender: SurrenderEarly}, "10S KH 6S 8D", 0, 1},
+		{"no early surrender against a six", Options{Surrender: SurrenderEarly}, "10S 6H 6S 8D", 0, 0},
+		{"no early surrender with late surrender", Options{Surrender: SurrenderLate}, "10S KH 6S 8D", 0, 0},
+	}
+	for _, tt := range tests {
+		ai := scriptAI{bet: 10}
+		if _, err := playStacked(t, tt.opts, tt.cards+" 2C 3C 4C", &ai); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if ai.insuranceOffers != tt.insurance || ai.surrenderOffers != tt.surrender {
+			t.Errorf("%s: want %d insurance and %d surrender offers, got %d and %d", tt.name, tt.insurance, tt.surrender, ai.insuranceOffers, ai.surrenderOffers)
+		}
+	}
+
+	// declining early surrender leaves the hand to be played as usual, and Play is only asked to play it
+	ai := &legalAI{scriptAI: scriptAI{bet: 10, moves: []Move{MoveHit, MoveStand}}}
+	g := New(Options{Surrender: SurrenderEarly})
+	ai.g = &g
+	stack, err := deck.ParseHand("10S AH 6S 8D 2C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.shoe = deck.NewShoe(1, func([]deck.Card) []deck.Card { return stack })
+	g.players = []*player{{ai: ai}}
+	if err := playRound(&g, true); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(ai.legal) != 2 || len(ai.hands[0]) != 3 {
+		t.Errorf("want the hand hit once and stood on after declining, got %v asked %d times", ai.hands, len(ai.legal))
+	}
+}
+
+func TestPlayRoundIllegal(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		cards string
+		ai    scriptAI
+	}{
+		{"bet below minimum", Options{MinBet: 5}, "10S 10H QS 7D", scriptAI{bet: 2}},
+		{"bet above maximum", Options{MaxBet: 5}, "10S 10H QS 7D", scriptAI{bet: 10}},
+		{"double outside 9 to 11", Options{Double: DoubleNineToEleven}, "6S 10H 6D 7D KC", scriptAI{bet: 10, moves: []Move{MoveDouble}}},
+		{"double after split", Options{NoDoubleAfterSplit: true}, "8S 10H 8D 7D 3C", scriptAI{bet: 10, moves: []Move{MoveSplit, MoveDouble}}},
+		{"split too many hands", Options{MaxSplitHands: 2}, "8S 10H 8D 7D 8C", scriptAI{bet: 10, moves: []Move{MoveSplit, MoveSplit}}},
+		{"split unpaired", Options{}, "8S 10H 9D 7D", scriptAI{bet: 10, moves: []Move{MoveSplit}}},
+		{"surrender not allowed", Options{}, "10S 10H 6S 7D", scriptAI{bet: 10, moves: []Move{MoveSurrender}}},
+	}
+	for _, tt := range tests {
+		ai := tt.ai
+		if _, err := playStacked(t, tt.opts, tt.cards, &ai); err == nil {
+			t.Errorf("%s: want an error, got nil", tt.name)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		cards string
+		moves []Move
+		hands string
+		want  int
+	}{
+		{"resplit", Options{}, "8S 10H 8D 7D 8C 3C 10C 2C", []Move{MoveSplit, MoveSplit}, "8S 3C, 8C 10C, 8D 2C", -10},
+		{"split aces stand", Options{}, "AS 10H AD 7D AC 9C", []Move{MoveSplit}, "AS AC, AD 9C", 0},
+		{"resplit aces", Options{ResplitAces: true}, "AS 10H AD 7D AC 9C 8C KC", []Move{MoveSplit, MoveSplit}, "AS 9C, AC 8C, AD KC", 30},
+	}
+	for _, tt := range tests {
+		ai := scriptAI{bet: 10, moves: tt.moves}
+		got, err := playStacked(t, tt.opts, tt.cards, &ai)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		var hands []string
+		for _, h := range ai.hands {
+			hands = append(hands, deck.FormatHand(h))
+		}
+		if strings.Join(hands, ", ") != tt.hands || got != tt.want {
+			t.Errorf("%s: want hands %s winning %d, got %s winning %d", tt.name, tt.hands, tt.want, strings.Join(hands, ", "), got)
+		}
+	}
+
+	// an illegal split ends the game with an error rather than a panic
+	stack, err := deck.ParseHand("8S 10H 9D 7D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New(Options{Hands: 1})
+	_, err = g.PlayShoe(deck.NewShoe(1, func([]deck.Card) []deck.Card { return stack }), Seat{AI: &scriptAI{bet: 10, moves: []Move{MoveSplit}}})
+	if err == nil {
+		t.Error("want an error splitting an unpaired hand, got nil")
+	}
+	g = New(Options{})
+	if err := MoveSplit(&g); err != errNoTurn {
+		t.Errorf("want %v splitting outside a player's turn, got %v", errNoTurn, err)
+	}
+}
+
+func TestMovesOutsideTurn(t *testing.T) {
+	// before the game is played no hand is being played, so every move is an error rather than a panic
+	for _, name := range []string{"hit", "stand", "double", "split", "surrender"} {
+		g := New(Options{Surrender: SurrenderLate})
+		move, _ := MoveByName(name)
+		if err := move(&g); err != errNoTurn {
+			t.Errorf("%s: want %v, got %v", name, errNoTurn, err)
+		}
+	}
+}
+
+func TestPlayRoundSeats(t *testing.T) {
+	// seats are dealt in order before the dealer, and play out in order before the dealer draws
+	first := &scriptAI{bet: 10}
+	second := &scriptAI{bet: 5, moves: []Move{MoveHit}}
+	third := &scriptAI{bet: 20, moves: []Move{MoveSplit, MoveStand, MoveStand}}
+	results, err := playStackedSeats(t, Options{}, "10S 9S 8C 10H QS 7S 8H 6D 2C JC KC 3D", first, second, third)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	// dealer draws 3 to 19: first has 20, second 18 and the third splits into 18 and 18
+	want := []Result{
+		{Balance: 10, Rounds: 1, Hands: 1, Wins: 1, Wagered: 10, SumSquares: 100},
+		{Balance: -5, Rounds: 1, Hands: 1, Losses: 1, Wagered: 5, Low: -5, SumSquares: 25},
+		{Balance: -40, Rounds: 1, Hands: 2, Losses: 2, Wagered: 40, Low: -40, SumSquares: 1600},
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("seat %d: want %+v, got %+v", i, want[i], results[i])
+		}
+	}
+}
+
+// legalAI records the legal moves each time it's asked to play.
+type legalAI struct {
+	scriptAI
+	g     *Game
+	legal [][]string
+}
+
+func (ai *legalAI) Play(hand []deck.Card, dealer deck.Card) Move {
+	ai.legal = append(ai.legal, ai.g.LegalMoves())
+	return ai.scriptAI.Play(hand, dealer)
+}
+
+func TestLegalMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		cards string
+		moves []Move
+		want  []string
+	}{
+		{"pair", Options{Surrender: SurrenderLate}, "8S 10H 8D 7D", nil, []string{"hit stand double split surrender"}},
+		{"after hitting", Options{}, "5S 10H 4D 7D 2C", []Move{MoveHit}, []string{"hit stand double", "hit stand"}},
+		{"split aces can resplit", Options{ResplitAces: true}, "AS 10H AD 7D AC 5C 5D", []Move{MoveSplit}, []string{"hit stand double split", "stand split"}},
+		{"double 10 or 11 only", Options{Double: DoubleTenToEleven}, "5S 10H 4D 7D", nil, []string{"hit stand"}},
+		{"early surrender", Options{Surrender: SurrenderEarly}, "5S 10H 4D 7D", nil, []string{"hit stand double surrender"}},
+	}
+	for _, tt := range tests {
+		stack, err := deck.ParseHand(tt.cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := New(tt.opts)
+		g.shoe = deck.NewShoe(1, func([]deck.Card) []deck.Card { return stack })
+		ai := &legalAI{scriptAI: scriptAI{bet: 10, moves: tt.moves}, g: &g}
+		g.players = []*player{{ai: ai}}
+		if err := playRound(&g, true); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		var got []string
+		for _, legal := range ai.legal {
+			got = append(got, strings.Join(legal, " "))
+		}
+		if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("%s: want legal moves %q, got %q", tt.name, tt.want, got)
+		}
+	}
+	if legal := (&Game{}).LegalMoves(); legal != nil {
+		t.Errorf("want no legal moves outside a player's turn, got %v", legal)
+	}
+}
+
+// bankrollAI bets up to 10, but never more than its balance.
+type bankrollAI struct {
+	scriptAI
+	balance int
+}
+
+func (ai *bankrollAI) ObserveBankroll(balance int) {
+	ai.balance = balance
+}
+
+func (ai *bankrollAI) Bet(shuffled bool) int {
+	if ai.balance < 10 {
+		return ai.balance
+	}
+	return 10
+}
+
+func TestStop(t *testing.T) {
+	game := New(Options{Decks: 6, Hands: 1000, Seed: 5})
+	results, err := game.Play(
+		Seat{AI: &bankrollAI{}, Bankroll: 30, Stop: Stop{Broke: true}},
+		Seat{AI: &bankrollAI{}, Bankroll: 100, Stop: Stop{Goal: 120, Broke: true}},
+		Seat{AI: &scriptAI{bet: 10}, Stop: Stop{Rounds: 25}},
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if r := results[0]; r.Stopped != StopBroke || r.Balance >= 1 || r.Rounds >= 1000 {
+		t.Errorf("seat 0: want to go broke, got %+v", r)
+	}
+	if r := results[1]; r.Stopped == StopGoal && r.Balance < 120 || r.Stopped == StopBroke && r.Balance >= 1 ||
+		r.Stopped == StopNone {
+		t.Errorf("seat 1: want to reach the goal or go broke, got %+v", r)
+	}
+	if r := results[2]; r.Stopped != StopRounds || r.Rounds != 25 {
+		t.Errorf("seat 2: want to stop after 25 rounds, got %+v", r)
+	}
+
+	game = New(Options{Hands: 10, Seed: 6})
+	results, err = game.Play(Seat{AI: &scriptAI{bet: 10}, Bankroll: 5, Stop: Stop{Broke: true}})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if r := results[0]; r.Stopped != StopBroke || r.Rounds != 0 {
+		t.Errorf("want to leave broke rather than bet more than the balance, got %+v", r)
+	}
+
+	game = New(Options{Hands: 10, Seed: 6})
+	results, err = game.Play(Seat{AI: &scriptAI{bet: LeaveTable}, Bankroll: 100}, Seat{AI: &scriptAI{bet: 10}})
+	if err != nil {
+		t.Fatal("unexpected error leaving:", err)
+	}
+	if r := results[0]; r.Stopped != StopLeft || r.Rounds != 0 || r.Balance != 100 {
+		t.Errorf("want to leave the table without playing, got %+v", r)
+	}
+	if r := results[1]; r.Stopped != StopNone || r.Rounds != 10 {
+		t.Errorf("want the other seat to play on, got %+v", r)
+	}
+}
+
+func TestNewShoe(t *testing.T) {
+	// a seeded game builds the same shoe every time
+	seeded := New(Options{Decks: 2, Seed: 7})
+	want := deck.FormatHand(seeded.NewShoe().Cards())
+	if got := deck.FormatHand(seeded.NewShoe().Cards()); got != want {
+		t.Errorf("seeded: want the same shoe from the same seed, got\n%s\n%s", want, got)
+	}
+
+	// a secure shuffle ignores the seed, and builds a different shoe every time
+	secure := New(Options{Decks: 2, Seed: 7, SecureShuffle: true})
+	if opts := secure.Options(); !opts.SecureShuffle || opts.Seed != 0 {
+		t.Errorf("secure: want a secure shuffle without a seed, got %+v", opts)
+	}
+	shoe := secure.NewShoe()
+	if shoe.Size() != 104 {
+		t.Errorf("secure: want a shoe of 104 cards, got %d", shoe.Size())
+	}
+	first, second := deck.FormatHand(shoe.Cards()), deck.FormatHand(secure.NewShoe().Cards())
+	if first == want || first == second {
+		t.Errorf("secure: want shoes which don't repeat or match the seeded one, got\n%s\n%s", first, second)
+	}
+	results, err := secure.PlayShoe(shoe, Seat{AI: &scriptAI{bet: 10}, Stop: Stop{Rounds: 20}})
+	if err != nil || results[0].Rounds != 20 {
+		t.Errorf("secure: want 20 rounds played, got %+v, %v", results[0], err)
+	}
+	if secure.Seed() != 0 {
+		t.Errorf("secure: want no seed after playing, got %d", secure.Seed())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		valid bool
+	}{
+		{"defaults", Options{}, true},
+		{"limits", Options{MinBet: 5, MaxBet: 500}, true},
+		{"negative minimum", Options{MinBet: -5}, false},
+		{"negative maximum", Options{MaxBet: -1}, false},
+		{"maximum below minimum", Options{MinBet: 10, MaxBet: 5}, false},
+		{"negative decks", Options{Decks: -1}, false},
+		{"negative payout", Options{BlackjackPayout: -1.5}, false},
+		{"negative split hands", Options{MaxSplitHands: -1}, false},
+		{"penetration over 1", Options{Penetration: 1.5}, false},
+		{"unknown surrender rule", Options{Surrender: SurrenderEarly + 1}, false},
+		{"unknown double rule", Options{Double: DoubleTenToEleven + 1}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.opts.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: want valid %v, got %v", tt.name, tt.valid, err)
+		}
+		// a bet of 0 is an error whatever the limits, and invalid options stop the game before it is made
+		g := New(tt.opts)
+		ai := &scriptAI{bet: 0}
+		results, err := g.Play(Seat{AI: ai, Bankroll: 100})
+		if err == nil || results[0].Rounds != 0 || results[0].Balance != 100 {
+			t.Errorf("%s: want an error without any rounds played for a bet of 0, got %+v, %v", tt.name, results[0], err)
+		}
+	}
+}
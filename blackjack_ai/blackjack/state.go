@@ -0,0 +1,82 @@
+package blackjack
+
+import "github.com/jeremy-miller/gophercises/deck"
+
+// Phase is the part of a round a game is in.
+type Phase uint8
+
+const (
+	PhaseBet            Phase = Phase(stateBet)
+	PhaseEarlySurrender Phase = Phase(stateEarlySurrender) // players are being offered early surrender
+	PhasePlayerTurn     Phase = Phase(statePlayerTurn)
+	PhaseDealerTurn     Phase = Phase(stateDealerTurn)
+	PhaseHandOver       Phase = Phase(stateHandOver)
+)
+
+// State is a snapshot of a game. It's a deep copy, so it can be kept and won't change as the game plays
+// on, and changing it has no effect on the game. It only holds what the players can see: until the
+// dealer's hole card is revealed, Dealer holds just the upcard.
+type State struct {
+	Phase     Phase
+	Round     int
+	Dealer    []deck.Card
+	HoleCard  bool // the dealer has a face down hole card, which isn't in Dealer
+	Seats     []SeatState
+	Turn      int // the index of the seat whose turn it is
+	Remaining int // the number of cards left in the shoe
+}
+
+// SeatState is a snapshot of a seat.
+type SeatState struct {
+	Hands     []HandState
+	Active    int // the index of the hand being played
+	Bet       int // the bet the seat made at the start of the round
+	Insurance int
+	Result    Result // the seat's results so far, including its balance
+}
+
+// HandState is a snapshot of one of a seat's hands.
+type HandState struct {
+	Cards       []deck.Card
+	Bet         int
+	Split       bool // the hand came from splitting a pair
+	Surrendered bool
+}
+
+// State returns a snapshot of the game. Front-ends can call it whenever their AI is asked for a decision.
+func (g *Game) State() State {
+	s := State{
+		Phase: Phase(g.state),
+		Round: g.round,
+		Turn:  g.cur,
+	}
+	if g.shoe != nil {
+		s.Remaining = g.shoe.Remaining()
+	}
+	dealer := g.dealer
+	if !g.holeRevealed && len(dealer) > 1 {
+		dealer = dealer[:1]
+		s.HoleCard = true
+	}
+	s.Dealer = append([]deck.Card(nil), dealer...)
+	s.Seats = make([]SeatState, len(g.players))
+	for i, p := range g.players {
+		seat := SeatState{
+			Hands:     make([]HandState, len(p.hands)),
+			Active:    p.handIdx,
+			Bet:       p.bet,
+			Insurance: p.insurance,
+			Result:    p.result,
+		}
+		for j, h := range p.hands {
+			seat.Hands[j] = HandState{
+				Cards:       append([]deck.Card(nil), h.cards...),
+				Bet:         h.bet,
+				Split:       h.split,
+				Surrendered: h.surrendered,
+			}
+		}
+		s.Seats[i] = seat
+	}
+	return s
+}
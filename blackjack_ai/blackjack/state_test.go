@@ -0,0 +1,57 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// stateAI keeps a snapshot of the game every time it's asked to play.
+type stateAI struct {
+	scriptAI
+	g      *Game
+	states []State
+}
+
+func (ai *stateAI) Play(hand []deck.Card, dealer deck.Card) Move {
+	ai.states = append(ai.states, ai.g.State())
+	return ai.scriptAI.Play(hand, dealer)
+}
+
+func TestState(t *testing.T) {
+	stack, err := deck.ParseHand("8S 10H 8D 9D 3C KC 10C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New(Options{})
+	g.shoe = deck.NewShoe(1, func([]deck.Card) []deck.Card { return stack })
+	ai := &stateAI{scriptAI: scriptAI{bet: 10, moves: []Move{MoveSplit, MoveStand, MoveStand}}, g: &g}
+	g.players = []*player{{ai: ai}}
+	if err := playRound(&g, true); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(ai.states) != 3 {
+		t.Fatalf("want 3 decisions, got %d", len(ai.states))
+	}
+	first := ai.states[0]
+	if first.Phase != PhasePlayerTurn || len(first.Dealer) != 1 || !first.HoleCard {
+		t.Errorf("want only the dealer's upcard during the player's turn, got %+v", first)
+	}
+	if first.Remaining != 3 || first.Seats[0].Bet != 10 || len(first.Seats[0].Hands) != 1 {
+		t.Errorf("unexpected state before splitting: %+v", first)
+	}
+	second := ai.states[2]
+	hands := second.Seats[0].Hands
+	if len(hands) != 2 || second.Seats[0].Active != 1 || !hands[1].Split || deck.FormatHand(hands[1].Cards) != "8D KC" {
+		t.Errorf("want the second split hand being played, got %+v", second.Seats[0])
+	}
+
+	// snapshots are deep copies
+	first.Seats[0].Hands[0].Cards[0] = deck.Card{Rank: deck.Ace, Suit: deck.Spade}
+	if deck.FormatHand(ai.states[1].Seats[0].Hands[0].Cards) != "8S 3C" {
+		t.Errorf("want snapshots independent of each other, got %v", ai.states[1].Seats[0].Hands[0].Cards)
+	}
+	if final := g.State(); final.Phase != PhaseHandOver || final.Seats[0].Result.Rounds != 1 {
+		t.Errorf("want the round over, got %+v", final)
+	}
+}
@@ -0,0 +1,127 @@
+package blackjack
+
+import "github.com/jeremy-miller/gophercises/deck"
+
+// DefaultBet is the bet a basic strategy AI makes unless it's given FlatBet. Bets of 10 are paid exactly for
+// 3:2 and 6:5 blackjacks and for surrenders, where smaller bets would be rounded down.
+const DefaultBet = 10
+
+// StrategyOption configures an AI returned by BasicStrategy.
+type StrategyOption func(*basicStrategy)
+
+// FlatBet makes a basic strategy AI bet the given amount every round.
+func FlatBet(bet int) StrategyOption {
+	return func(ai *basicStrategy) {
+		ai.bet = bet
+	}
+}
+
+// BasicStrategy returns an AI which flat bets DefaultBet and plays every hand by the chart, never taking
+// insurance. The table's rules are needed to know which of the chart's actions are allowed, e.g. whether a
+// hand can be doubled after splitting. A nil chart uses the built in chart for the rules.
+func BasicStrategy(chart *Chart, opts Options, strategyOpts ...StrategyOption) AI {
+	if chart == nil {
+		chart = ChartFor(opts)
+	}
+	if opts.MaxSplitHands == 0 {
+		opts.MaxSplitHands = 4
+	}
+	ai := &basicStrategy{
+		chart: chart,
+		opts:  opts,
+		bet:   DefaultBet,
+	}
+	for _, opt := range strategyOpts {
+		opt(ai)
+	}
+	return ai
+}
+
+type basicStrategy struct {
+	chart *Chart
+	opts  Options
+	bet   int
+
+	// the AI only sees one hand at a time, so it keeps track of its splits during the round
+	hands     int
+	splitAces bool
+}
+
+func (ai *basicStrategy) Bet(shuffled bool) int {
+	ai.hands = 1
+	ai.splitAces = false
+	return ai.bet
+}
+
+func (ai *basicStrategy) Insurance(hand []deck.Card) bool {
+	return false
+}
+
+// Surrender surrenders early whenever the chart says to surrender the hand.
+func (ai *basicStrategy) Surrender(hand []deck.Card, dealer deck.Card) bool {
+	switch ai.chart.Lookup(hand, dealer, true) {
+	case ActionSurrender, ActionSurrenderOrStand, ActionSurrenderOrSplit:
+		return true
+	}
+	return false
+}
+
+func (ai *basicStrategy) Play(hand []deck.Card, dealer deck.Card) Move {
+	split := ai.hands > 1
+	canDouble := len(hand) == 2 && !(split && ai.opts.NoDoubleAfterSplit)
+	if canDouble {
+		switch score := Score(hand...); ai.opts.Double {
+		case DoubleNineToEleven:
+			canDouble = score >= 9 && score <= 11
+		case DoubleTenToEleven:
+			canDouble = score >= 10 && score <= 11
+		}
+	}
+	canSplit := len(hand) == 2 && hand[0].Rank == hand[1].Rank && ai.hands < ai.opts.MaxSplitHands &&
+		!(ai.splitAces && !ai.opts.ResplitAces)
+	canSurrender := len(hand) == 2 && !split && ai.opts.Surrender != SurrenderNone
+
+	action := ai.chart.Lookup(hand, dealer, canSplit)
+	switch action {
+	case ActionStand:
+		return MoveStand
+	case ActionDouble, ActionDoubleOrStand:
+		if canDouble {
+			return MoveDouble
+		}
+		if action == ActionDoubleOrStand {
+			return MoveStand
+		}
+		return MoveHit
+	case ActionSplit, ActionSplitIfDAS, ActionSurrenderOrSplit:
+		if action == ActionSurrenderOrSplit && canSurrender {
+			return MoveSurrender
+		}
+		if action == ActionSplitIfDAS && ai.opts.NoDoubleAfterSplit {
+			return MoveHit
+		}
+		return ai.split(hand)
+	case ActionSurrender, ActionSurrenderOrStand:
+		if canSurrender {
+			return MoveSurrender
+		}
+		if action == ActionSurrenderOrStand {
+			return MoveStand
+		}
+		return MoveHit
+	default:
+		return MoveHit
+	}
+}
+
+func (ai *basicStrategy) split(hand []deck.Card) Move {
+	ai.hands++
+	if hand[0].Rank == deck.Ace {
+		ai.splitAces = true
+	}
+	return MoveSplit
+}
+
+func (ai *basicStrategy) Results(hand [][]deck.Card, dealer []deck.Card) {
+	// noop
+}
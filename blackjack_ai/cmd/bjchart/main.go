@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+)
+
+func main() {
+	decks := flag.Int("decks", 6, "the number of decks in the shoe")
+	s17 := flag.Bool("s17", false, "the dealer stands on soft 17")
+	noDAS := flag.Bool("nodas", false, "disallow doubling after splitting")
+	surrender := flag.String("surrender", "none", "the surrender rule: none or late")
+	noHoleCard := flag.Bool("nohole", false, "the dealer doesn't take a hole card or peek for blackjack")
+	verify := flag.Bool("verify", false, "compare the derived chart with the built in chart for the rules instead of printing it")
+	flag.Parse()
+
+	opts := blackjack.Options{
+		Decks:              *decks,
+		StandSoft17:        *s17,
+		NoDoubleAfterSplit: *noDAS,
+		NoHoleCard:         *noHoleCard,
+	}
+	switch *surrender {
+	case "none":
+	case "late":
+		opts.Surrender = blackjack.SurrenderLate
+	default:
+		exit(fmt.Sprintf("Unknown surrender rule %q", *surrender))
+	}
+
+	derived := blackjack.NewAnalyzer(blackjack.NewComposition(*decks), opts).DeriveChart()
+	if !*verify {
+		if err := derived.WriteCSV(os.Stdout); err != nil {
+			exit(err.Error())
+		}
+		return
+	}
+	chart := blackjack.ChartFor(opts)
+	differences := compare("hard", chart.Hard, derived.Hard, opts) +
+		compare("soft", chart.Soft, derived.Soft, opts) +
+		compare("pair", chart.Pairs, derived.Pairs, opts)
+	fmt.Printf("%d differences from the %s chart\n", differences, chart.Name)
+}
+
+// compare prints every cell where the charts play differently under the rules and returns how many there are.
+func compare(table string, want, got map[int]blackjack.Row, opts blackjack.Options) int {
+	upcards := []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "A"}
+	n := 0
+	for total := 2; total <= 21; total++ {
+		row, ok := want[total]
+		if !ok {
+			continue
+		}
+		for i, action := range row {
+			if action.For(opts) != got[total][i].For(opts) {
+				fmt.Printf("%s %d against %s: chart %s, derived %s\n", table, total, upcards[i], action, got[total][i])
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func exit(msg string) {
+	fmt.Println(msg)
+	os.Exit(1)
+}
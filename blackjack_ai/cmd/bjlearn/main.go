@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/blackjack_ai/learn"
+)
+
+func main() {
+	rounds := flag.Int("rounds", 1000000, "the number of rounds to train for")
+	policyFile := flag.String("policy", "", "a policy file to continue training from, if it exists, and save to")
+	chartFile := flag.String("chart", "", "a CSV file to export the learned chart to")
+	epsilon := flag.Float64("epsilon", 0.1, "the chance of trying a random move while training")
+	seed := flag.Int64("seed", 0, "the seed for the shoe and the random moves; 0 picks one from the current time")
+	decks := flag.Int("decks", 6, "the number of decks in the shoe")
+	s17 := flag.Bool("s17", false, "the dealer stands on soft 17")
+	noDAS := flag.Bool("nodas", false, "disallow doubling after splitting")
+	surrender := flag.Bool("surrender", false, "allow late surrender")
+	flag.Parse()
+
+	opts := blackjack.Options{
+		Decks:              *decks,
+		StandSoft17:        *s17,
+		NoDoubleAfterSplit: *noDAS,
+	}
+	if *surrender {
+		opts.Surrender = blackjack.SurrenderLate
+	}
+
+	policy := learn.NewPolicy()
+	if *policyFile != "" {
+		f, err := os.Open(*policyFile)
+		switch {
+		case err == nil:
+			policy, err = learn.LoadPolicy(f)
+			f.Close()
+			if err != nil {
+				exit(err.Error())
+			}
+		case !os.IsNotExist(err):
+			exit(err.Error())
+		}
+	}
+
+	err := learn.Train(policy, learn.Config{
+		Options: opts,
+		Rounds:  *rounds,
+		Epsilon: *epsilon,
+		Seed:    *seed,
+	})
+	if err != nil {
+		exit(err.Error())
+	}
+	if *policyFile != "" {
+		if err := write(*policyFile, policy.Save); err != nil {
+			exit(err.Error())
+		}
+	}
+	chart := policy.Chart("learned")
+	if *chartFile != "" {
+		if err := write(*chartFile, chart.WriteCSV); err != nil {
+			exit(err.Error())
+		}
+	}
+
+	basic := blackjack.ChartFor(opts)
+	same, cells := 0, 0
+	for _, tables := range [][2]map[int]blackjack.Row{
+		{basic.Hard, chart.Hard}, {basic.Soft, chart.Soft}, {basic.Pairs, chart.Pairs},
+	} {
+		for total, row := range tables[0] {
+			for i, action := range row {
+				cells++
+				if action.For(opts) == tables[1][total][i].For(opts) {
+					same++
+				}
+			}
+		}
+	}
+	fmt.Printf("The learned chart agrees with the %s chart on %d of %d cells\n", basic.Name, same, cells)
+}
+
+// write creates the file and writes to it with fn.
+func write(path string, fn func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func exit(msg string) {
+	fmt.Println(msg)
+	os.Exit(1)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/blackjack_ai/history"
+)
+
+func main() {
+	historyFile := flag.String("history", "history.jsonl", "a hand history recorded while playing blackjack")
+	chartFile := flag.String("chart", "", "a CSV or YAML strategy chart to replay with; defaults to the built in chart for the recorded rules")
+	flag.Parse()
+
+	var chart *blackjack.Chart
+	if *chartFile != "" {
+		var err error
+		chart, err = blackjack.LoadChart(*chartFile)
+		if err != nil {
+			exit(err.Error())
+		}
+	}
+	f, err := os.Open(*historyFile)
+	if err != nil {
+		exit(err.Error())
+	}
+	defer f.Close()
+	report, err := history.Replay(f, func(opts blackjack.Options) blackjack.AI {
+		return blackjack.BasicStrategy(chart, opts)
+	})
+	if err != nil {
+		exit(err.Error())
+	}
+	for _, d := range report.Divergences {
+		fmt.Println(d)
+	}
+	fmt.Println("Rounds:", report.Rounds)
+	fmt.Println("Recorded winnings:", report.RecordedNet)
+	fmt.Println("Replayed winnings:", report.ReplayedNet)
+	fmt.Printf("Difference per round: %+.4f\n", report.EVDifference())
+}
+
+func exit(msg string) {
+	fmt.Println(msg)
+	os.Exit(1)
+}
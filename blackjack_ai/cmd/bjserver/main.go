@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/server"
+)
+
+func main() {
+	port := flag.Int("port", 3000, "the port to start the blackjack server on")
+	maxTables := flag.Int("tables", server.DefaultMaxTables, "the most tables open at once")
+	idle := flag.Duration("idle", server.DefaultIdleTimeout, "how long a table is kept without any requests")
+	flag.Parse()
+
+	s := server.New()
+	s.MaxTables = *maxTables
+	s.IdleTimeout = *idle
+	fmt.Printf("Starting the server on port %d\n", *port)
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *port), s))
+}
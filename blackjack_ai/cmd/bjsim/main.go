@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/betting"
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/blackjack_ai/counting"
+	"github.com/jeremy-miller/gophercises/blackjack_ai/sim"
+)
+
+func main() {
+	rounds := flag.Int("rounds", 1000000, "the total number of rounds to simulate")
+	session := flag.Int("session", 1000, "the number of rounds in each session, each with a fresh shoe and bankroll")
+	bankroll := flag.Int("bankroll", 0, "the bankroll each session starts with, in betting units; 0 skips the risk of ruin")
+	seed := flag.Int64("seed", 0, "the seed for the first session's shoe; 0 picks one from the current time")
+	workers := flag.Int("workers", 0, "the number of sessions to play at once; 0 uses every CPU")
+	decks := flag.Int("decks", 6, "the number of decks in the shoe")
+	s17 := flag.Bool("s17", false, "the dealer stands on soft 17")
+	noDAS := flag.Bool("nodas", false, "disallow doubling after splitting")
+	surrender := flag.String("surrender", "none", "the surrender rule: none, late or early")
+	payout := flag.Float64("payout", 1.5, "the payout for a blackjack")
+	chartFile := flag.String("chart", "", "a CSV or YAML strategy chart to play; defaults to the built in chart for the rules")
+	format := flag.String("format", "text", "the report format: text, json or csv")
+	betFlag := flag.String("bet", "flat", "the betting strategy: flat, martingale, paroli or kelly (half Kelly on the Hi-Lo true count)")
+	minBet := flag.Int("min", 1, "the table minimum, in betting units")
+	maxBet := flag.Int("max", 0, "the table maximum, in betting units; 0 means there is no maximum")
+	bustOut := flag.Bool("bustout", false, "end a session once it can't cover its next bet")
+	goal := flag.Int("goal", 0, "end a session once its balance reaches this many units; 0 means there is no goal")
+	unit := flag.Int("unit", blackjack.DefaultBet, "the size of a betting unit in chips; units smaller than 10 have blackjack and surrender payouts rounded down")
+	flag.Parse()
+	if *unit <= 0 {
+		exit("The betting unit must be positive")
+	}
+
+	opts := blackjack.Options{
+		Decks:              *decks,
+		BlackjackPayout:    *payout,
+		StandSoft17:        *s17,
+		NoDoubleAfterSplit: *noDAS,
+		MinBet:             *minBet * *unit,
+		MaxBet:             *maxBet * *unit,
+	}
+	switch *surrender {
+	case "none":
+	case "late":
+		opts.Surrender = blackjack.SurrenderLate
+	case "early":
+		opts.Surrender = blackjack.SurrenderEarly
+	default:
+		exit(fmt.Sprintf("Unknown surrender rule %q", *surrender))
+	}
+	var chart *blackjack.Chart
+	if *chartFile != "" {
+		var err error
+		chart, err = blackjack.LoadChart(*chartFile)
+		if err != nil {
+			exit(err.Error())
+		}
+	}
+
+	// the betting strategy makes every bet, in betting units, and basic strategy plays the hands
+	var strategy betting.Strategy
+	switch *betFlag {
+	case "flat":
+		strategy = betting.Flat{Unit: *unit}
+	case "martingale":
+		strategy = betting.Martingale{Unit: *unit}
+	case "paroli":
+		strategy = betting.Paroli{Unit: *unit}
+	case "kelly":
+		strategy = betting.Kelly{Unit: *unit, Fraction: 0.5}
+	default:
+		exit(fmt.Sprintf("Unknown betting strategy %q", *betFlag))
+	}
+	newAI := func() blackjack.AI {
+		return &betting.AI{
+			AI:            blackjack.BasicStrategy(chart, opts, blackjack.FlatBet(*unit)),
+			Strategy:      strategy,
+			Counter:       counting.NewCounter(counting.HiLo, *decks),
+			Min:           opts.MinBet,
+			Max:           opts.MaxBet,
+			CapToBankroll: *bustOut,
+		}
+	}
+
+	report, err := sim.Run(sim.Config{
+		Options:       opts,
+		AI:            newAI,
+		Rounds:        *rounds,
+		Unit:          *unit,
+		SessionRounds: *session,
+		Bankroll:      *bankroll * *unit,
+		Stop:          blackjack.Stop{Broke: *bustOut, Goal: *goal * *unit},
+		Seed:          *seed,
+		Workers:       *workers,
+	})
+	if err != nil {
+		exit(err.Error())
+	}
+	switch *format {
+	case "json":
+		err = report.WriteJSON(os.Stdout)
+	case "csv":
+		err = report.WriteCSV(os.Stdout, true)
+	default:
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		exit(err.Error())
+	}
+}
+
+func exit(msg string) {
+	fmt.Println(msg)
+	os.Exit(1)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/blackjack_ai/tui"
+	"github.com/mitchellh/go-homedir"
+)
+
+func main() {
+	home, _ := homedir.Dir()
+	statsFile := flag.String("stats", filepath.Join(home, ".blackjack.json"), "the file the bankroll and stats are saved in between sessions")
+	decks := flag.Int("decks", 6, "the number of decks in the shoe")
+	reset := flag.Bool("reset", false, "start over with a fresh bankroll and stats")
+	flag.Parse()
+
+	stats, err := tui.LoadStats(*statsFile)
+	if err != nil {
+		exit(err.Error())
+	}
+	if *reset {
+		stats = tui.Stats{Bankroll: tui.DefaultBankroll, Best: tui.DefaultBankroll}
+	}
+	game := blackjack.New(blackjack.Options{
+		Decks:     *decks,
+		Hands:     math.MaxInt32, // play until the player quits
+		Surrender: blackjack.SurrenderLate,
+	})
+	if err := tui.New(&game, os.Stdin, os.Stdout, &stats, *statsFile).Run(); err != nil {
+		exit(err.Error())
+	}
+	fmt.Println("\nThanks for playing! Your bankroll is", stats.Bankroll)
+}
+
+func exit(msg string) {
+	fmt.Println(msg)
+	os.Exit(1)
+}
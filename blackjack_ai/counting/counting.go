@@ -0,0 +1,180 @@
+// Package counting implements blackjack card counting systems which can be used to size the bets of a blackjack.AI.
+package counting
+
+import (
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// System is a card counting system: the tag added to the running count for each card value seen.
+type System struct {
+	Name string
+	// Tags holds the tag for each blackjack card value, from Tags[1] for an ace to Tags[10] for tens and faces.
+	Tags [11]int
+	// Balanced systems sum to 0 over a full deck and need converting to a true count. Unbalanced systems
+	// instead start the running count at an offset which depends on the number of decks.
+	Balanced bool
+}
+
+var (
+	HiLo = System{
+		Name:     "Hi-Lo",
+		Tags:     [11]int{1: -1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 10: -1},
+		Balanced: true,
+	}
+	KO = System{
+		Name: "KO",
+		Tags: [11]int{1: -1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 1, 10: -1},
+	}
+	OmegaII = System{
+		Name:     "Omega II",
+		Tags:     [11]int{2: 1, 3: 1, 4: 2, 5: 2, 6: 2, 7: 1, 9: -1, 10: -2},
+		Balanced: true,
+	}
+	Zen = System{
+		Name:     "Zen",
+		Tags:     [11]int{1: -1, 2: 1, 3: 1, 4: 2, 5: 2, 6: 2, 7: 1, 10: -2},
+		Balanced: true,
+	}
+)
+
+// Tag returns the amount the card adds to the running count.
+func (s System) Tag(c deck.Card) int {
+	value := int(c.Rank)
+	if value > 10 {
+		value = 10
+	}
+	return s.Tags[value]
+}
+
+// Counter keeps the running count of a shoe of the given number of decks.
+type Counter struct {
+	System System
+	Decks  int
+
+	running int
+	seen    int
+}
+
+// NewCounter returns a counter for a freshly shuffled shoe.
+func NewCounter(sys System, decks int) *Counter {
+	c := &Counter{
+		System: sys,
+		Decks:  decks,
+	}
+	c.Reset()
+	return c
+}
+
+// Reset starts the count over, which should be done every time the shoe is shuffled.
+func (c *Counter) Reset() {
+	c.running = 0
+	if !c.System.Balanced {
+		c.running = 4 - 4*c.Decks // the standard initial running count for KO
+	}
+	c.seen = 0
+}
+
+// Observe counts cards which have been seen.
+func (c *Counter) Observe(cards ...deck.Card) {
+	for _, card := range cards {
+		c.running += c.System.Tag(card)
+		c.seen++
+	}
+}
+
+// RunningCount returns the sum of the tags of every card seen since the last Reset.
+func (c *Counter) RunningCount() int {
+	return c.running
+}
+
+// DecksRemaining estimates the number of decks left in the shoe from the cards seen so far. It never
+// returns less than a quarter of a deck so the true count stays meaningful at the end of a shoe.
+func (c *Counter) DecksRemaining() float64 {
+	remaining := float64(c.Decks*52-c.seen) / 52
+	if remaining < 0.25 {
+		return 0.25
+	}
+	return remaining
+}
+
+// TrueCount returns the running count per deck remaining. Unbalanced systems are designed to be used
+// without this conversion, so for them the running count is returned unchanged.
+func (c *Counter) TrueCount() float64 {
+	if !c.System.Balanced {
+		return float64(c.running)
+	}
+	return float64(c.running) / c.DecksRemaining()
+}
+
+// Spread maps counts to bet sizes, as a list of steps ordered by increasing Count.
+type Spread []Step
+
+// Step bets Units when the count is at least Count.
+type Step struct {
+	Count float64
+	Units int
+}
+
+// Units returns the number of units to bet at the given count: the units of the last step whose count
+// has been reached, or of the first step if none has.
+func (s Spread) Units(count float64) int {
+	if len(s) == 0 {
+		return 1
+	}
+	units := s[0].Units
+	for _, step := range s {
+		if count < step.Count {
+			break
+		}
+		units = step.Units
+	}
+	return units
+}
+
+// AI wraps another blackjack.AI, which still makes every playing decision, and sizes its bets from
+// the count of every card seen at the table.
+type AI struct {
+	blackjack.AI
+	Counter *Counter
+	Spread  Spread
+	Unit    int // the size of one betting unit
+}
+
+// Bet resets the count when the shoe was shuffled and bets according to the spread. The wrapped AI's
+// Bet is still called, so it can prepare for the round, but its bet is ignored.
+func (ai *AI) Bet(shuffled bool) int {
+	if shuffled {
+		ai.Counter.Reset()
+	}
+	ai.AI.Bet(shuffled)
+	unit := ai.Unit
+	if unit == 0 {
+		unit = 1
+	}
+	return unit * ai.Spread.Units(ai.Counter.TrueCount())
+}
+
+// ObserveCard implements blackjack.CardObserver, counting the card and passing it on to the wrapped
+// AI if it is an observer as well.
+func (ai *AI) ObserveCard(card deck.Card) {
+	ai.Counter.Observe(card)
+	if o, ok := ai.AI.(blackjack.CardObserver); ok {
+		o.ObserveCard(card)
+	}
+}
+
+// ObserveEvent implements blackjack.Observer, passing the event on to the wrapped AI if it is an observer.
+func (ai *AI) ObserveEvent(e blackjack.Event) {
+	if o, ok := ai.AI.(blackjack.Observer); ok {
+		o.ObserveEvent(e)
+	}
+}
+
+// ObserveBankroll implements blackjack.BankrollObserver, passing the balance on to the wrapped AI if it is
+// an observer.
+func (ai *AI) ObserveBankroll(balance int) {
+	if o, ok := ai.AI.(blackjack.BankrollObserver); ok {
+		o.ObserveBankroll(balance)
+	}
+}
@@ -0,0 +1,101 @@
+package counting
+
+import (
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+func TestSystemsOverFullDeck(t *testing.T) {
+	// balanced systems end a deck at 0, KO ends 4 higher than it started
+	tests := []struct {
+		sys  System
+		want int
+	}{
+		{HiLo, 0}, {OmegaII, 0}, {Zen, 0}, {KO, 4},
+	}
+	for _, tt := range tests {
+		c := NewCounter(tt.sys, 1)
+		c.Observe(deck.New()...)
+		if c.RunningCount() != tt.want {
+			t.Errorf("%s: want running count %d, got %d", tt.sys.Name, tt.want, c.RunningCount())
+		}
+	}
+}
+
+func TestTrueCount(t *testing.T) {
+	c := NewCounter(HiLo, 6)
+	fives := deck.New(deck.Deck(2), deck.Filter(func(c deck.Card) bool { return c.Rank != deck.Five }))
+	c.Observe(fives...) // 8 fives, 5 and a bit decks remain
+	want := 8 / (float64(6*52-8) / 52)
+	if c.TrueCount() != want {
+		t.Errorf("TrueCount(): want %f, got %f", want, c.TrueCount())
+	}
+	c.Reset()
+	if c.RunningCount() != 0 || c.DecksRemaining() != 6 {
+		t.Errorf("Reset(): want running count 0 with 6 decks, got %d with %f", c.RunningCount(), c.DecksRemaining())
+	}
+	if ko := NewCounter(KO, 6); ko.RunningCount() != -20 {
+		t.Errorf("KO: want initial running count -20 for 6 decks, got %d", ko.RunningCount())
+	}
+}
+
+func TestSpread(t *testing.T) {
+	spread := Spread{{Count: 1, Units: 1}, {Count: 2, Units: 4}, {Count: 4, Units: 12}}
+	tests := map[float64]int{-3: 1, 1.5: 1, 2: 4, 3.9: 4, 10: 12}
+	for count, want := range tests {
+		if got := spread.Units(count); got != want {
+			t.Errorf("Units(%f): want %d, got %d", count, want, got)
+		}
+	}
+}
+
+type standAI struct{}
+
+func (standAI) Bet(shuffled bool) int { return 1 }
+
+func (standAI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move { return blackjack.MoveStand }
+
+func (standAI) Insurance(hand []deck.Card) bool                   { return false }
+func (standAI) Surrender(hand []deck.Card, dealer deck.Card) bool { return false }
+
+func (standAI) Results(hand [][]deck.Card, dealer []deck.Card) {}
+
+func TestAIObservesTable(t *testing.T) {
+	ai := &AI{
+		AI:      standAI{},
+		Counter: NewCounter(HiLo, 1),
+		Spread:  Spread{{Count: 0, Units: 1}},
+	}
+	game := blackjack.New(blackjack.Options{Decks: 1, Hands: 1, Seed: 1})
+	if _, err := game.Play(blackjack.Seat{AI: ai}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	// both player cards and the dealer's whole hand, including the hole card
+	if ai.Counter.seen < 4 {
+		t.Errorf("want every card at the table counted, got %d", ai.Counter.seen)
+	}
+}
+
+// observingAI stands, recording what it's told as every kind of observer.
+type observingAI struct {
+	standAI
+	cards, events, balances int
+}
+
+func (ai *observingAI) ObserveCard(card deck.Card)     { ai.cards++ }
+func (ai *observingAI) ObserveEvent(e blackjack.Event) { ai.events++ }
+func (ai *observingAI) ObserveBankroll(balance int)    { ai.balances++ }
+
+func TestAIForwardsObservers(t *testing.T) {
+	inner := &observingAI{}
+	ai := &AI{AI: inner, Counter: NewCounter(HiLo, 1)}
+	game := blackjack.New(blackjack.Options{Decks: 1, Hands: 3, Seed: 1})
+	if _, err := game.Play(blackjack.Seat{AI: ai, Bankroll: 100}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if inner.cards == 0 || inner.events == 0 || inner.balances != 3 {
+		t.Errorf("want the wrapped AI to observe cards, events and 3 balances, got %+v", *inner)
+	}
+}
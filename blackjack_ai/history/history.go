@@ -0,0 +1,188 @@
+// Package history records every round of a blackjack game as JSON Lines and replays recorded rounds
+// against other AIs.
+//
+// A history starts with a header line holding the game's rules, including the seed of its shoe, and
+// then has one line per round.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Header is the first line of a history.
+type Header struct {
+	Options blackjack.Options `json:"options"`
+}
+
+// Round is the record of a single round.
+type Round struct {
+	Round int `json:"round"`
+	// Shuffle and Position locate the round in the game's sequence of shoes: the shoe had been shuffled
+	// Shuffle times, counting the first, and Position cards had been dealt from it when the round began.
+	Shuffle  int         `json:"shuffle"`
+	Position int         `json:"position"`
+	Dealer   []deck.Card `json:"dealer"`
+	Seats    []Seat      `json:"seats"`
+}
+
+// Seat is what happened at one seat during a round.
+type Seat struct {
+	Bet       int    `json:"bet"`
+	Insurance int    `json:"insurance,omitempty"`
+	Hands     []Hand `json:"hands"`
+	Net       int    `json:"net"` // the seat's winnings for the round, including insurance
+}
+
+// Hand is one of a seat's hands; there is more than one after splitting.
+type Hand struct {
+	Cards     []deck.Card       `json:"cards"`
+	Bet       int               `json:"bet"`
+	Decisions []Decision        `json:"decisions,omitempty"`
+	Outcome   blackjack.Outcome `json:"outcome"`
+	Net       int               `json:"net"`
+}
+
+// Decision is a move made by a seat's AI, along with the hand it was made on.
+type Decision struct {
+	Hand []deck.Card `json:"hand"`
+	Move string      `json:"move"`
+}
+
+// Recorder is a blackjack.Observer which builds a Round from the events of each round.
+type Recorder struct {
+	onRound func(Round) error
+	err     error
+
+	shuffles int
+	position int
+	cur      *Round
+}
+
+// Record writes the history of every round played by the game to w. Errors writing the history don't
+// stop the game, and are returned by the recorder's Err method instead.
+func Record(w io.Writer, g *blackjack.Game) *Recorder {
+	enc := json.NewEncoder(w)
+	header := false
+	r := &Recorder{onRound: func(round Round) error {
+		if !header {
+			header = true
+			if err := enc.Encode(Header{Options: g.Options()}); err != nil {
+				return err
+			}
+		}
+		return enc.Encode(round)
+	}}
+	g.Observe(r)
+	return r
+}
+
+// Err returns the first error writing the history.
+func (r *Recorder) Err() error {
+	return r.err
+}
+
+// ObserveEvent implements blackjack.Observer.
+func (r *Recorder) ObserveEvent(e blackjack.Event) {
+	if e.Type == blackjack.EventShuffle {
+		r.shuffles++
+		r.position = 0
+		return
+	}
+	if r.cur == nil {
+		r.cur = &Round{Round: e.Round, Shuffle: r.shuffles, Position: r.position}
+	}
+	switch e.Type {
+	case blackjack.EventBet:
+		r.seat(e.Seat).Bet = e.Amount
+		r.seat(e.Seat).Hands = []Hand{{Bet: e.Amount}}
+	case blackjack.EventInsurance:
+		r.seat(e.Seat).Insurance = e.Amount
+	case blackjack.EventDeal:
+		r.position++
+		switch {
+		case e.Seat != blackjack.Dealer:
+			h := r.hand(e.Seat, e.Hand)
+			h.Cards = append(h.Cards, e.Card)
+		case !e.Hole: // the hole card is recorded when it's revealed
+			r.cur.Dealer = append(r.cur.Dealer, e.Card)
+		}
+	case blackjack.EventReveal:
+		r.cur.Dealer = append(r.cur.Dealer, e.Card)
+	case blackjack.EventMove:
+		if e.Seat == blackjack.Dealer {
+			return
+		}
+		h := r.hand(e.Seat, e.Hand)
+		h.Decisions = append(h.Decisions, Decision{
+			Hand: append([]deck.Card(nil), h.Cards...),
+			Move: e.Move,
+		})
+		switch e.Move {
+		case "double":
+			h.Bet *= 2
+		case "split":
+			s := r.seat(e.Seat)
+			next := Hand{Cards: []deck.Card{h.Cards[1]}, Bet: h.Bet}
+			h.Cards = h.Cards[:1]
+			s.Hands = append(s.Hands, Hand{})
+			copy(s.Hands[e.Hand+2:], s.Hands[e.Hand+1:])
+			s.Hands[e.Hand+1] = next
+		}
+	case blackjack.EventSettle:
+		s := r.seat(e.Seat)
+		s.Net += e.Amount
+		if e.Outcome != blackjack.OutcomeInsurance {
+			h := r.hand(e.Seat, e.Hand)
+			h.Outcome = e.Outcome
+			h.Net = e.Amount
+		}
+	case blackjack.EventRoundOver:
+		if err := r.onRound(*r.cur); err != nil && r.err == nil {
+			r.err = err
+		}
+		r.cur = nil
+	}
+}
+
+func (r *Recorder) seat(i int) *Seat {
+	for len(r.cur.Seats) <= i {
+		r.cur.Seats = append(r.cur.Seats, Seat{})
+	}
+	return &r.cur.Seats[i]
+}
+
+func (r *Recorder) hand(seat, i int) *Hand {
+	return &r.seat(seat).Hands[i]
+}
+
+// Reader reads a history one round at a time.
+type Reader struct {
+	Header Header
+	dec    *json.Decoder
+}
+
+// NewReader reads the history's header, ready for the rounds to be read with Next.
+func NewReader(r io.Reader) (*Reader, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	var h Header
+	if err := dec.Decode(&h); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("history: missing header")
+		}
+		return nil, err
+	}
+	return &Reader{Header: h, dec: dec}, nil
+}
+
+// Next returns the next round of the history, or io.EOF once there are no more.
+func (r *Reader) Next() (Round, error) {
+	var round Round
+	err := r.dec.Decode(&round)
+	return round, err
+}
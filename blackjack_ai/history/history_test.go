@@ -0,0 +1,156 @@
+package history
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// record plays a game with two basic strategy seats and returns its history.
+func record(t *testing.T, opts blackjack.Options) ([]blackjack.Result, *bytes.Buffer) {
+	var buf bytes.Buffer
+	game := blackjack.New(opts)
+	rec := Record(&buf, &game)
+	results, err := game.Play(
+		blackjack.Seat{AI: blackjack.BasicStrategy(nil, opts)},
+		blackjack.Seat{AI: blackjack.BasicStrategy(nil, opts)},
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatal("unexpected error recording:", err)
+	}
+	return results, &buf
+}
+
+func TestRecord(t *testing.T) {
+	opts := blackjack.Options{Decks: 2, Hands: 300, Seed: 11, Surrender: blackjack.SurrenderLate}
+	results, buf := record(t, opts)
+	if lines := strings.Count(buf.String(), "\n"); lines != 301 {
+		t.Errorf("want a header and 300 rounds, got %d lines", lines)
+	}
+	hr, err := NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.Header.Options.Seed != 11 || hr.Header.Options.MaxSplitHands != 4 {
+		t.Errorf("want the game's options with defaults in the header, got %+v", hr.Header.Options)
+	}
+	net := make([]int, 2)
+	shuffles, splits := 0, 0
+	for {
+		round, err := hr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if round.Shuffle > shuffles {
+			shuffles = round.Shuffle
+			if round.Position != 0 {
+				t.Errorf("round %d: want the first round after a shuffle at position 0, got %d", round.Round, round.Position)
+			}
+		}
+		if len(round.Dealer) < 2 {
+			t.Errorf("round %d: want the dealer's whole hand, got %v", round.Round, round.Dealer)
+		}
+		for i, seat := range round.Seats {
+			net[i] += seat.Net
+			hands := 0
+			for _, h := range seat.Hands {
+				hands += h.Net
+				if len(h.Cards) < 2 {
+					t.Errorf("round %d: want every hand to have at least 2 cards, got %v", round.Round, h.Cards)
+				}
+			}
+			if seat.Insurance == 0 && hands != seat.Net {
+				t.Errorf("round %d: want the seat's net to be the sum of its hands, got %d and %d", round.Round, seat.Net, hands)
+			}
+			splits += len(seat.Hands) - 1
+		}
+	}
+	for i := range results {
+		if net[i] != results[i].Balance {
+			t.Errorf("seat %d: want the recorded rounds to add up to %d, got %d", i, results[i].Balance, net[i])
+		}
+	}
+	if shuffles < 2 || splits == 0 {
+		t.Errorf("want a history covering several shoes and splits, got %d shoes and %d splits", shuffles, splits)
+	}
+}
+
+// standAI stands on everything.
+type standAI struct{}
+
+func (standAI) Bet(shuffled bool) int                                  { return 1 }
+func (standAI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move { return blackjack.MoveStand }
+func (standAI) Insurance(hand []deck.Card) bool                        { return false }
+func (standAI) Surrender(hand []deck.Card, dealer deck.Card) bool      { return false }
+func (standAI) Results(hand [][]deck.Card, dealer []deck.Card)         {}
+
+func TestReplay(t *testing.T) {
+	opts := blackjack.Options{Decks: 6, Hands: 500, Seed: 3, Surrender: blackjack.SurrenderLate}
+	results, buf := record(t, opts)
+	history := buf.String()
+
+	same, err := Replay(strings.NewReader(history), func(opts blackjack.Options) blackjack.AI {
+		return blackjack.BasicStrategy(nil, opts)
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(same.Divergences) != 0 || same.RecordedNet != same.ReplayedNet {
+		t.Errorf("want the same AI to replay the history exactly, got %d divergences and %d against %d",
+			len(same.Divergences), same.ReplayedNet, same.RecordedNet)
+	}
+	if same.Rounds != 500 || same.RecordedNet != results[0].Balance+results[1].Balance {
+		t.Errorf("want 500 rounds netting %d, got %d netting %d", results[0].Balance+results[1].Balance, same.Rounds, same.RecordedNet)
+	}
+
+	stand, err := Replay(strings.NewReader(history), func(blackjack.Options) blackjack.AI { return standAI{} })
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(stand.Divergences) == 0 || stand.EVDifference() >= 0 {
+		t.Errorf("want standing on everything to diverge and do worse, got %d divergences and %f per round",
+			len(stand.Divergences), stand.EVDifference())
+	}
+	for _, d := range stand.Divergences {
+		if d.Replayed == d.Recorded || (d.Replayed != "stand" && d.Replayed != "") {
+			t.Errorf("unexpected divergence: %v", d)
+			break
+		}
+	}
+}
+
+func TestReplayUnseeded(t *testing.T) {
+	_, secure := record(t, blackjack.Options{Decks: 2, Hands: 10, SecureShuffle: true})
+	histories := map[string]string{
+		"secure shuffle": secure.String(),
+		"no seed":        `{"options":{"Decks":2}}` + "\n",
+	}
+	for name, history := range histories {
+		_, err := Replay(strings.NewReader(history), func(opts blackjack.Options) blackjack.AI {
+			return blackjack.BasicStrategy(nil, opts)
+		})
+		if err == nil {
+			t.Errorf("%s: want an error, got nil", name)
+		}
+	}
+}
+
+func TestDivergenceString(t *testing.T) {
+	hand, _ := deck.ParseHand("10S 6H")
+	dealer, _ := deck.ParseCard("10D")
+	d := Divergence{Round: 4, Seat: 1, Cards: hand, Dealer: dealer, Recorded: "surrender", Replayed: "hit"}
+	want := "round 4, seat 1, hand 0: 10S 6H against 10D: recorded surrender, replayed hit"
+	if d.String() != want {
+		t.Errorf("want %q, got %q", want, d.String())
+	}
+}
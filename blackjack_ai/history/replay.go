@@ -0,0 +1,151 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Divergence is the first decision in a round where the replaying AI chose differently from the history.
+// The cards dealt after it differ, so later decisions in the round aren't compared.
+type Divergence struct {
+	Round    int
+	Seat     int
+	Hand     int
+	Cards    []deck.Card // the hand the decision was made on
+	Dealer   deck.Card   // the dealer's upcard
+	Recorded string      // the recorded move, or "" if the recorded hand was already over
+	Replayed string      // the replaying AI's move, or "" if its hand was already over
+}
+
+func (d Divergence) String() string {
+	move := func(m string) string {
+		if m == "" {
+			return "nothing"
+		}
+		return m
+	}
+	return fmt.Sprintf("round %d, seat %d, hand %d: %s against %s: recorded %s, replayed %s",
+		d.Round, d.Seat, d.Hand, deck.FormatHand(d.Cards), deck.FormatHand([]deck.Card{d.Dealer}),
+		move(d.Recorded), move(d.Replayed))
+}
+
+// Report compares the outcome of a history with the outcome of replaying it.
+type Report struct {
+	Rounds      int
+	RecordedNet int
+	ReplayedNet int
+	Divergences []Divergence
+}
+
+// EVDifference returns how much more the replaying AI won per round than the recorded one.
+func (r Report) EVDifference() float64 {
+	if r.Rounds == 0 {
+		return 0
+	}
+	return float64(r.ReplayedNet-r.RecordedNet) / float64(r.Rounds)
+}
+
+// Replay plays every round of a history again, dealt from the same point in the same shoe, with each seat
+// played by an AI returned by newAI for the recorded rules. Each seat keeps its AI for the whole history,
+// and bets what was bet in the history so that only the playing decisions are compared. Histories of
+// games shuffled securely, or without a recorded seed, can't be dealt again and return an error.
+func Replay(r io.Reader, newAI func(opts blackjack.Options) blackjack.AI) (Report, error) {
+	var report Report
+	hr, err := NewReader(r)
+	if err != nil {
+		return report, err
+	}
+	opts := hr.Header.Options
+	if opts.SecureShuffle || opts.Seed == 0 {
+		return report, errors.New("history: the shoe can't be dealt again without the seed it was shuffled with")
+	}
+	opts.Hands = 1
+	shoe := deck.NewShoe(opts.Penetration, deck.Deck(opts.Decks), deck.Seed(opts.Seed))
+	shuffles := 1
+	var ais []*replayAI
+	for {
+		recorded, err := hr.Next()
+		if err == io.EOF {
+			return report, nil
+		}
+		if err != nil {
+			return report, err
+		}
+		for ; shuffles < recorded.Shuffle; shuffles++ {
+			shoe.Shuffle()
+		}
+		s := shoe.Clone()
+		if err := s.Burn(recorded.Position); err != nil {
+			return report, fmt.Errorf("history: round %d: %w", recorded.Round, err)
+		}
+
+		seats := make([]blackjack.Seat, len(recorded.Seats))
+		for i, seat := range recorded.Seats {
+			if i == len(ais) {
+				ais = append(ais, &replayAI{AI: newAI(hr.Header.Options)})
+			}
+			ais[i].bet = seat.Bet
+			seats[i] = blackjack.Seat{AI: ais[i]}
+		}
+		var replayed Round
+		game := blackjack.New(opts)
+		game.Observe(&Recorder{onRound: func(round Round) error {
+			replayed = round
+			return nil
+		}})
+		if _, err := game.PlayShoe(s, seats...); err != nil {
+			return report, fmt.Errorf("history: round %d: %w", recorded.Round, err)
+		}
+
+		report.Rounds++
+		for i := range recorded.Seats {
+			report.RecordedNet += recorded.Seats[i].Net
+			report.ReplayedNet += replayed.Seats[i].Net
+			if d, ok := diverge(recorded.Seats[i], replayed.Seats[i]); ok {
+				d.Round, d.Seat, d.Dealer = recorded.Round, i, recorded.Dealer[0]
+				report.Divergences = append(report.Divergences, d)
+			}
+		}
+	}
+}
+
+// diverge returns the first decision which differs between two plays of a seat.
+func diverge(recorded, replayed Seat) (Divergence, bool) {
+	for i := 0; i < len(recorded.Hands) || i < len(replayed.Hands); i++ {
+		var a, b []Decision
+		if i < len(recorded.Hands) {
+			a = recorded.Hands[i].Decisions
+		}
+		if i < len(replayed.Hands) {
+			b = replayed.Hands[i].Decisions
+		}
+		for j := 0; j < len(a) || j < len(b); j++ {
+			d := Divergence{Hand: i}
+			if j < len(a) {
+				d.Cards, d.Recorded = a[j].Hand, a[j].Move
+			}
+			if j < len(b) {
+				d.Cards, d.Replayed = b[j].Hand, b[j].Move
+			}
+			if d.Recorded != d.Replayed {
+				return d, true
+			}
+		}
+	}
+	return Divergence{}, false
+}
+
+// replayAI makes the recorded bet, leaving the wrapped AI to make every other decision.
+type replayAI struct {
+	blackjack.AI
+	bet int
+}
+
+func (ai *replayAI) Bet(shuffled bool) int {
+	ai.AI.Bet(shuffled)
+	return ai.bet
+}
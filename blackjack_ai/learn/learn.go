@@ -0,0 +1,224 @@
+// Package learn trains blackjack strategies by Q-learning from play against the blackjack engine, and turns
+// what was learned into a strategy chart which can be compared with, or played like, basic strategy.
+package learn
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// actions are the moves which can be learned, indexed as in Values.
+var actions = [...]string{"hit", "stand", "double", "split", "surrender"}
+
+const (
+	hit = iota
+	stand
+	double
+	split
+	surrender
+)
+
+// Table is the part of a strategy chart a hand is played from.
+type Table string
+
+const (
+	Hard Table = "hard"
+	Soft Table = "soft"
+	Pair Table = "pair"
+)
+
+// State is what the learner knows when deciding how to play a hand, which is the same as a chart cell: the
+// table, the hand's total (or the value of one card of a pair, with 11 for aces) and the dealer's upcard,
+// from 2 to 11 for an ace.
+type State struct {
+	Table  Table
+	Total  int
+	Upcard int
+}
+
+// stateOf returns the state for a hand, looking pairs up by their total when they can't be split.
+func stateOf(hand []deck.Card, dealer deck.Card, canSplit bool) State {
+	s := State{Table: Hard, Total: blackjack.Score(hand...), Upcard: value(dealer)}
+	switch {
+	case canSplit:
+		s.Table, s.Total = Pair, value(hand[0])
+	case blackjack.Soft(hand...) && s.Total >= 13:
+		s.Table = Soft
+	case s.Total < 5:
+		s.Total = 5
+	}
+	return s
+}
+
+// value returns the value of a card as used in a State.
+func value(c deck.Card) int {
+	if c.Rank == deck.Ace {
+		return 11
+	}
+	return blackjack.Score(c)
+}
+
+// Values holds the learned value of each action in a state, in units of the original bet, and the number
+// of times each value was updated.
+type Values struct {
+	Q [len(actions)]float64
+	N [len(actions)]int
+}
+
+// Policy is a table of learned values.
+type Policy struct {
+	Values map[State]*Values
+}
+
+// NewPolicy returns a policy which hasn't learned anything.
+func NewPolicy() *Policy {
+	return &Policy{Values: make(map[State]*Values)}
+}
+
+func (p *Policy) values(s State) *Values {
+	v, ok := p.Values[s]
+	if !ok {
+		v = &Values{}
+		p.Values[s] = v
+	}
+	return v
+}
+
+// best returns the action with the highest value out of the legal ones.
+func (p *Policy) best(s State, legal []int) int {
+	v := p.values(s)
+	best := legal[0]
+	for _, a := range legal[1:] {
+		if v.Q[a] > v.Q[best] {
+			best = a
+		}
+	}
+	return best
+}
+
+// entry is the layout of a state's values when saved.
+type entry struct {
+	State
+	Values
+}
+
+// Save writes the policy as JSON.
+func (p *Policy) Save(w io.Writer) error {
+	entries := make([]entry, 0, len(p.Values))
+	for s, v := range p.Values {
+		entries = append(entries, entry{s, *v})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// LoadPolicy reads a policy written by Save.
+func LoadPolicy(r io.Reader) (*Policy, error) {
+	var entries []entry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("learn: reading policy: %w", err)
+	}
+	p := NewPolicy()
+	for _, e := range entries {
+		switch e.Table {
+		case Hard, Soft, Pair:
+		default:
+			return nil, fmt.Errorf("learn: reading policy: unknown table %q", e.Table)
+		}
+		v := e.Values
+		p.Values[e.State] = &v
+	}
+	return p, nil
+}
+
+// Chart returns the policy as a strategy chart, picking the action with the highest learned value in each
+// cell. Doubling and surrendering are only picked if they've been tried, and the chart's fallbacks say what
+// to do when they aren't allowed.
+func (p *Policy) Chart(name string) *blackjack.Chart {
+	c := &blackjack.Chart{
+		Name:  name,
+		Hard:  make(map[int]blackjack.Row),
+		Soft:  make(map[int]blackjack.Row),
+		Pairs: make(map[int]blackjack.Row),
+	}
+	row := func(table Table, total int) blackjack.Row {
+		var r blackjack.Row
+		for i := range r {
+			v, ok := p.Values[State{Table: table, Total: total, Upcard: i + 2}]
+			if !ok {
+				v = &Values{}
+			}
+			e := blackjack.Expectation{
+				Hit:          v.Q[hit],
+				Stand:        v.Q[stand],
+				Double:       v.Q[double],
+				Split:        v.Q[split],
+				Surrender:    v.Q[surrender],
+				CanDouble:    v.N[double] > 0,
+				CanSplit:     v.N[split] > 0,
+				CanSurrender: v.N[surrender] > 0,
+			}
+			r[i], _ = e.Best()
+		}
+		return r
+	}
+	for total := 5; total <= 21; total++ {
+		c.Hard[total] = row(Hard, total)
+	}
+	for total := 13; total <= 21; total++ {
+		c.Soft[total] = row(Soft, total)
+	}
+	for v := 2; v <= 11; v++ {
+		c.Pairs[v] = row(Pair, v)
+	}
+	return c
+}
+
+// AI returns an AI which plays the policy's chart with basic strategy.
+func (p *Policy) AI(opts blackjack.Options) blackjack.AI {
+	return blackjack.BasicStrategy(p.Chart("learned"), opts)
+}
+
+// Config describes a training session.
+type Config struct {
+	// Options are the table rules. Options.Hands is set to Rounds.
+	Options blackjack.Options
+	// Rounds is the number of rounds to play.
+	Rounds int
+	// Epsilon is the chance of trying a random legal move instead of the best one; defaults to 0.1.
+	Epsilon float64
+	// Alpha is the learning rate. If 0, each value is the average of every update to it.
+	Alpha float64
+	// Seed seeds the shoe and the random moves. If 0, one is chosen from the time.
+	Seed int64
+}
+
+// Train improves the policy by playing against the engine.
+func Train(p *Policy, cfg Config) error {
+	if cfg.Rounds <= 0 {
+		return fmt.Errorf("learn: the number of rounds must be positive")
+	}
+	if cfg.Epsilon == 0 {
+		cfg.Epsilon = 0.1
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	opts := cfg.Options
+	opts.Hands = cfg.Rounds
+	opts.Seed = cfg.Seed
+	game := blackjack.New(opts)
+	l := NewLearner(&game, p)
+	l.Epsilon = cfg.Epsilon
+	l.Alpha = cfg.Alpha
+	l.rand = rand.New(rand.NewSource(cfg.Seed))
+	_, err := game.Play(blackjack.Seat{AI: l})
+	return err
+}
@@ -0,0 +1,76 @@
+package learn
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+)
+
+func TestTrain(t *testing.T) {
+	p := NewPolicy()
+	opts := blackjack.Options{StandSoft17: true}
+	if err := Train(p, Config{Options: opts, Rounds: 300000, Seed: 1}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	c := p.Chart("learned")
+	// cells which are far enough from their alternatives to be learned quickly
+	tests := []struct {
+		name string
+		got  blackjack.Action
+		want blackjack.Action
+	}{
+		{"hard 20 against 10", c.Hard[20][8], blackjack.ActionStand},
+		{"hard 19 against 7", c.Hard[19][5], blackjack.ActionStand},
+		{"hard 11 against 6", c.Hard[11][4], blackjack.ActionDouble},
+		{"hard 10 against 5", c.Hard[10][3], blackjack.ActionDouble},
+		{"hard 6 against 10", c.Hard[6][8], blackjack.ActionHit},
+		{"soft 20 against 9", c.Soft[20][7], blackjack.ActionStand},
+		{"aces against 6", c.Pairs[11][4], blackjack.ActionSplit},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: want %q, got %q", tt.name, tt.want, tt.got)
+		}
+	}
+
+	// the learned policy plays like basic strategy
+	game := blackjack.New(blackjack.Options{StandSoft17: true, Hands: 1000, Seed: 2})
+	if _, err := game.Play(blackjack.Seat{AI: p.AI(opts)}); err != nil {
+		t.Error("unexpected error playing the learned chart:", err)
+	}
+}
+
+func TestTrainPlaysLegally(t *testing.T) {
+	for _, opts := range []blackjack.Options{
+		{Surrender: blackjack.SurrenderLate, MaxSplitHands: 4, ResplitAces: true},
+		{Surrender: blackjack.SurrenderEarly, NoDoubleAfterSplit: true, Double: blackjack.DoubleTenToEleven},
+		{NoHoleCard: true, Decks: 1},
+	} {
+		if err := Train(NewPolicy(), Config{Options: opts, Rounds: 20000, Seed: 3, Epsilon: 0.5}); err != nil {
+			t.Errorf("%+v: unexpected error: %v", opts, err)
+		}
+	}
+}
+
+func TestPolicySaveLoad(t *testing.T) {
+	p := NewPolicy()
+	if err := Train(p, Config{Rounds: 2000, Seed: 4}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	var buf bytes.Buffer
+	if err := p.Save(&buf); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	got, err := LoadPolicy(&buf)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !reflect.DeepEqual(got.Values, p.Values) {
+		t.Error("loaded policy differs from the saved one")
+	}
+	if _, err := LoadPolicy(bytes.NewBufferString(`[{"Table": "firm"}]`)); err == nil {
+		t.Error("want an error for an unknown table, got nil")
+	}
+}
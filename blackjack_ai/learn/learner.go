@@ -0,0 +1,174 @@
+package learn
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// bet is the learner's flat bet, which rewards are divided by.
+const bet = 10
+
+// Learner is an AI which plays by its policy, with some random exploration, and updates the policy from
+// the results. It asks the game for the legal moves, and learns from the game's events, so it must only
+// play at the game it was created with.
+//
+// Every decision's value is updated with Q-learning: a hit towards the best value of the hand's next
+// state, and a move which finishes the hand towards the hand's result. A split is updated towards the sum
+// of the results of the hands it made. Early surrender is always declined.
+type Learner struct {
+	Policy  *Policy
+	Epsilon float64 // the chance of trying a random legal move
+	Alpha   float64 // the learning rate; if 0, values are averages
+
+	game *blackjack.Game
+	rand *rand.Rand
+
+	seat    int
+	seated  bool
+	pending *step    // the decision just made, until its move event says which hand it was for
+	hands   []*track // the learner's hands this round, in the game's order
+}
+
+// NewLearner returns a learner to play at the game.
+func NewLearner(g *blackjack.Game, p *Policy) *Learner {
+	return &Learner{
+		Policy:  p,
+		Epsilon: 0.1,
+		game:    g,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// step is a decision, along with the legal actions it was chosen from.
+type step struct {
+	state  State
+	action int
+	legal  []int
+}
+
+// track follows the decisions on one of the learner's hands.
+type track struct {
+	last   *step      // the latest decision, which is waiting for the hand's next state or result
+	parent *splitNode // the split which made the hand, if any
+}
+
+// splitNode adds up the results of the hands made by a split.
+type splitNode struct {
+	step
+	left   int // the number of hands still to be settled
+	sum    float64
+	parent *splitNode
+}
+
+func (l *Learner) Bet(shuffled bool) int {
+	l.hands = []*track{{}}
+	l.pending = nil
+	return bet
+}
+
+func (l *Learner) Insurance(hand []deck.Card) bool {
+	return false
+}
+
+func (l *Learner) Surrender(hand []deck.Card, dealer deck.Card) bool {
+	return false
+}
+
+func (l *Learner) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	var legal []int
+	canSplit := false
+	for _, name := range l.game.LegalMoves() {
+		for a, action := range actions {
+			if action == name {
+				legal = append(legal, a)
+				canSplit = canSplit || a == split
+			}
+		}
+	}
+	s := stateOf(hand, dealer, canSplit)
+	action := l.Policy.best(s, legal)
+	if l.rand.Float64() < l.Epsilon {
+		action = legal[l.rand.Intn(len(legal))]
+	}
+	l.pending = &step{state: s, action: action, legal: legal}
+	move, _ := blackjack.MoveByName(actions[action])
+	return move
+}
+
+func (l *Learner) Results(hands [][]deck.Card, dealer []deck.Card) {
+	// noop, the results are learned from the settle events
+}
+
+// ObserveEvent implements blackjack.Observer, learning from the learner's moves and results.
+func (l *Learner) ObserveEvent(e blackjack.Event) {
+	switch e.Type {
+	case blackjack.EventMove:
+		if l.pending == nil {
+			return
+		}
+		// the first move after the learner plays is its own
+		l.seat, l.seated = e.Seat, true
+		l.move(e.Hand, l.pending)
+		l.pending = nil
+	case blackjack.EventSettle:
+		if !l.seated || e.Seat != l.seat || e.Outcome == blackjack.OutcomeInsurance || e.Hand >= len(l.hands) {
+			return
+		}
+		t := l.hands[e.Hand]
+		r := float64(e.Amount) / bet
+		if t.last != nil {
+			l.update(t.last, r)
+		}
+		l.credit(t.parent, r)
+	case blackjack.EventRoundOver:
+		l.hands = nil
+	}
+}
+
+// move records a decision made on a hand, updating the hand's previous decision now its next state is known.
+func (l *Learner) move(hand int, s *step) {
+	if hand >= len(l.hands) {
+		return
+	}
+	t := l.hands[hand]
+	if t.last != nil {
+		next := l.Policy.values(s.state)
+		l.update(t.last, next.Q[l.Policy.best(s.state, s.legal)])
+	}
+	if s.action != split {
+		t.last = s
+		return
+	}
+	node := &splitNode{step: *s, left: 2, parent: t.parent}
+	l.hands = append(l.hands, nil)
+	copy(l.hands[hand+2:], l.hands[hand+1:])
+	l.hands[hand] = &track{parent: node}
+	l.hands[hand+1] = &track{parent: node}
+}
+
+// credit adds a hand's result to the split which made it, updating the split once all its hands are settled.
+func (l *Learner) credit(node *splitNode, r float64) {
+	if node == nil {
+		return
+	}
+	node.sum += r
+	node.left--
+	if node.left == 0 {
+		l.update(&node.step, node.sum)
+		l.credit(node.parent, node.sum)
+	}
+}
+
+// update moves the value of a decision towards the target.
+func (l *Learner) update(s *step, target float64) {
+	v := l.Policy.values(s.state)
+	v.N[s.action]++
+	alpha := l.Alpha
+	if alpha == 0 {
+		alpha = 1 / float64(v.N[s.action])
+	}
+	v.Q[s.action] += alpha * (target - v.Q[s.action])
+}
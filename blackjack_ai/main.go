@@ -1,18 +1,41 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
 
 	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/blackjack_ai/history"
 )
 
 func main() {
+	historyFile := flag.String("history", "", "a file to record the hand history to, which can be replayed with bjreplay")
+	flag.Parse()
+
 	opts := blackjack.Options{
 		Decks:           3,
 		Hands:           2,
 		BlackjackPayout: 1.5,
 	}
 	game := blackjack.New(opts)
-	winnings := game.Play(blackjack.HumanAI())
-	fmt.Println("Winnings:", winnings)
+	var rec *history.Recorder
+	if *historyFile != "" {
+		f, err := os.Create(*historyFile)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		rec = history.Record(f, &game)
+	}
+	results, err := game.Play(blackjack.Seat{AI: blackjack.HumanAI()})
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	if rec != nil && rec.Err() != nil {
+		fmt.Println("Error recording history:", rec.Err())
+	}
+	fmt.Println("Winnings:", results[0].Balance)
+	fmt.Println("Seed:", game.Seed()) // pass back in Options.Seed to replay these hands
 }
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+)
+
+// Client is a client for the blackjack API served by Server.
+type Client struct {
+	BaseURL    string       // e.g. "http://localhost:3000"
+	HTTPClient *http.Client // defaults to http.DefaultClient
+}
+
+// NewTable sits down at a new table with the given rules and bankroll.
+func (c *Client) NewTable(opts blackjack.Options, bankroll int) (State, error) {
+	return c.do(http.MethodPost, "/tables", NewTable{Options: opts, Bankroll: bankroll})
+}
+
+// State returns the state of a table.
+func (c *Client) State(id string) (State, error) {
+	return c.do(http.MethodGet, "/tables/"+id, nil)
+}
+
+// Bet places a bet at a table which is waiting for one.
+func (c *Client) Bet(id string, amount int) (State, error) {
+	return c.do(http.MethodPost, "/tables/"+id+"/bet", Bet{Amount: amount})
+}
+
+// Insurance takes or declines insurance at a table which is offering it.
+func (c *Client) Insurance(id string, take bool) (State, error) {
+	return c.do(http.MethodPost, "/tables/"+id+"/insurance", Insurance{Take: take})
+}
+
+// Surrender takes or declines early surrender at a table which is offering it.
+func (c *Client) Surrender(id string, take bool) (State, error) {
+	return c.do(http.MethodPost, "/tables/"+id+"/surrender", Surrender{Take: take})
+}
+
+// Move makes a move at a table which is waiting for one: hit, stand, double, split or surrender.
+func (c *Client) Move(id string, move string) (State, error) {
+	return c.do(http.MethodPost, "/tables/"+id+"/move", Move{Move: move})
+}
+
+// Leave leaves a table, which ends its game, and returns its final state.
+func (c *Client) Leave(id string) (State, error) {
+	return c.do(http.MethodDelete, "/tables/"+id, nil)
+}
+
+func (c *Client) do(method, path string, body interface{}) (State, error) {
+	var state State
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return state, err
+		}
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.BaseURL, "/")+path, &buf)
+	if err != nil {
+		return state, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return state, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 400 {
+		var e Error
+		if err := json.NewDecoder(res.Body).Decode(&e); err != nil || e.Error == "" {
+			return state, fmt.Errorf("blackjack server: %s", res.Status)
+		}
+		return state, errors.New(e.Error)
+	}
+	err = json.NewDecoder(res.Body).Decode(&state)
+	return state, err
+}
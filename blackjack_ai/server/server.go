@@ -0,0 +1,282 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+)
+
+// The limits a server is created with by New.
+const (
+	DefaultMaxTables   = 1000
+	DefaultMaxDecks    = 8
+	DefaultMaxHands    = 10000
+	DefaultIdleTimeout = 30 * time.Minute
+)
+
+// The limits on the rules a table can be created with, beyond the server's own limits, which keep the game
+// to rules a real table might offer.
+const (
+	maxPayout     = 2.0 // a blackjack pays at most 2:1
+	maxSplitHands = 8
+)
+
+// maxBodySize is the largest request body read, far larger than any valid request.
+const maxBodySize = 1 << 16
+
+// Server is an http.Handler serving the blackjack API. Create one with New; its limits can be changed
+// before it serves its first request.
+type Server struct {
+	MaxTables   int           // the most tables open at once
+	MaxDecks    int           // the most decks a table's shoe can hold
+	MaxHands    int           // the most rounds a table can play
+	IdleTimeout time.Duration // how long a table is kept without any requests before it's closed
+
+	mu       sync.Mutex
+	tables   map[string]*lockedTable
+	creating int // tables being created, which count towards MaxTables
+}
+
+// lockedTable serializes the requests made to a table.
+type lockedTable struct {
+	sync.Mutex
+	*table
+	used  time.Time // when the last request was made
+	timer *time.Timer
+}
+
+// New returns a server with no tables and the default limits.
+func New() *Server {
+	return &Server{
+		MaxTables:   DefaultMaxTables,
+		MaxDecks:    DefaultMaxDecks,
+		MaxHands:    DefaultMaxHands,
+		IdleTimeout: DefaultIdleTimeout,
+		tables:      make(map[string]*lockedTable),
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "tables" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if len(parts) == 1 {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.create(w, r)
+		return
+	}
+	s.mu.Lock()
+	t, ok := s.tables[parts[1]]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "no such table")
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.used = time.Now()
+	action := ""
+	if len(parts) == 3 {
+		action = parts[2]
+	}
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, t.snapshot())
+	case action == "" && r.Method == http.MethodDelete:
+		t.timer.Stop()
+		t.leave()
+		s.remove(parts[1], t)
+		writeJSON(w, http.StatusOK, t.snapshot())
+	case action == "bet" && r.Method == http.MethodPost:
+		var req Bet
+		if decode(w, r, &req) {
+			placeBet(w, t.table, req)
+		}
+	case action == "insurance" && r.Method == http.MethodPost:
+		var req Insurance
+		if decode(w, r, &req) && expect(w, t.table, PhaseInsurance) {
+			t.decide(decision{insurance: req.Take})
+			writeJSON(w, http.StatusOK, t.snapshot())
+		}
+	case action == "surrender" && r.Method == http.MethodPost:
+		var req Surrender
+		if decode(w, r, &req) && expect(w, t.table, PhaseSurrender) {
+			t.decide(decision{surrender: req.Take})
+			writeJSON(w, http.StatusOK, t.snapshot())
+		}
+	case action == "move" && r.Method == http.MethodPost:
+		var req Move
+		if decode(w, r, &req) {
+			makeMove(w, t.table, req)
+		}
+	case action == "" || action == "bet" || action == "insurance" || action == "surrender" || action == "move":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var req NewTable
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Bankroll <= 0 {
+		writeError(w, http.StatusBadRequest, "the bankroll must be positive")
+		return
+	}
+	if err := s.validate(req.Options); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.mu.Lock()
+	if len(s.tables)+s.creating >= s.MaxTables {
+		s.mu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, "too many tables are open; try again later")
+		return
+	}
+	s.creating++
+	s.mu.Unlock()
+	t := &lockedTable{table: newTable(id, req.Options, req.Bankroll), used: time.Now()}
+	t.Lock()
+	defer t.Unlock()
+	t.wait()
+	s.mu.Lock()
+	s.creating--
+	s.tables[id] = t
+	s.mu.Unlock()
+	t.timer = time.AfterFunc(s.IdleTimeout, func() { s.expire(id, t) })
+	writeJSON(w, http.StatusCreated, t.snapshot())
+}
+
+// validate returns an error if the rules aren't valid, or are outside what the server allows.
+func (s *Server) validate(opts blackjack.Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	switch {
+	case opts.Decks > s.MaxDecks:
+		return fmt.Errorf("the number of decks must be between 1 and %d", s.MaxDecks)
+	case opts.Hands > s.MaxHands:
+		return fmt.Errorf("the number of hands must be between 1 and %d", s.MaxHands)
+	case opts.BlackjackPayout != 0 && (opts.BlackjackPayout < 1 || opts.BlackjackPayout > maxPayout):
+		return fmt.Errorf("the blackjack payout must be between 1 and %g", maxPayout)
+	case opts.MaxSplitHands > maxSplitHands:
+		return fmt.Errorf("the number of split hands must be between 1 and %d", maxSplitHands)
+	}
+	return nil
+}
+
+// expire closes the table if it hasn't been used for the idle timeout, or checks again once it could have
+// been.
+func (s *Server) expire(id string, t *lockedTable) {
+	t.Lock()
+	defer t.Unlock()
+	if idle := time.Since(t.used); idle < s.IdleTimeout {
+		t.timer.Reset(s.IdleTimeout - idle)
+		return
+	}
+	t.leave()
+	s.remove(id, t)
+}
+
+// remove removes the table from the server, unless it has already gone.
+func (s *Server) remove(id string, t *lockedTable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tables[id] == t {
+		delete(s.tables, id)
+	}
+}
+
+func placeBet(w http.ResponseWriter, t *table, req Bet) {
+	if !expect(w, t, PhaseBet) {
+		return
+	}
+	opts := t.state.Options
+	switch {
+	case req.Amount < opts.MinBet:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("the minimum bet is %d", opts.MinBet))
+	case opts.MaxBet > 0 && req.Amount > opts.MaxBet:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("the maximum bet is %d", opts.MaxBet))
+	case req.Amount > t.state.Balance:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("can't bet more than the balance of %d", t.state.Balance))
+	default:
+		t.decide(decision{bet: req.Amount})
+		writeJSON(w, http.StatusOK, t.snapshot())
+	}
+}
+
+func makeMove(w http.ResponseWriter, t *table, req Move) {
+	if !expect(w, t, PhasePlay) {
+		return
+	}
+	move, ok := blackjack.MoveByName(req.Move)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown move %q", req.Move))
+		return
+	}
+	legal := false
+	for _, m := range t.state.Moves {
+		legal = legal || m == req.Move
+	}
+	if !legal {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("can't %s now; the legal moves are %s", req.Move, strings.Join(t.state.Moves, ", ")))
+		return
+	}
+	t.decide(decision{move: move})
+	writeJSON(w, http.StatusOK, t.snapshot())
+}
+
+// expect writes an error and returns false if the table isn't waiting for the given decision.
+func expect(w http.ResponseWriter, t *table, phase Phase) bool {
+	if t.state.Phase != phase {
+		writeError(w, http.StatusConflict, fmt.Sprintf("the table is waiting for %s, not %s", t.state.Phase, phase))
+		return false
+	}
+	return true
+}
+
+// decode reads the request's JSON body into v, writing an error and returning false if it can't.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, Error{Error: msg})
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+)
+
+func newClient(t *testing.T, s *Server) (*Client, func()) {
+	ts := httptest.NewServer(s)
+	return &Client{BaseURL: ts.URL, HTTPClient: ts.Client()}, ts.Close
+}
+
+func TestPlayGame(t *testing.T) {
+	c, done := newClient(t, New())
+	defer done()
+	state, err := c.NewTable(blackjack.Options{Decks: 1, Hands: 20}, 1000)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if state.Phase != PhaseBet || state.Options.Seed != 0 || state.Options.MinBet != 1 {
+		t.Fatalf("want a new table waiting for a bet without giving away its seed, got %+v", state)
+	}
+	id := state.ID
+	rounds, played, net := 0, 0, 0
+	commitments := map[string]bool{state.Commitment: true}
+	for state.Phase != PhaseOver {
+		switch state.Phase {
+		case PhaseBet:
+			rounds++
+			state, err = c.Bet(id, 10)
+		case PhaseInsurance:
+			state, err = c.Insurance(id, false)
+		case PhasePlay:
+			played++
+			if len(state.Dealer) != 1 {
+				t.Errorf("want only the dealer's upcard while playing, got %v", state.Dealer)
+			}
+			move := "stand"
+			if state.Moves[0] == "hit" && len(state.Hands[state.Active].Cards) == 2 {
+				move = "hit"
+			}
+			state, err = c.Move(id, move)
+		}
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if state.Commitment != "" {
+			commitments[state.Commitment] = true
+		}
+		if r := state.Revealed; r != nil && (!commitments[r.Commitment] || !r.Verify()) {
+			t.Errorf("want a revealed shoe to match an earlier commitment, got %+v", r)
+		}
+		// a round is over once the table waits for the next bet, or the game ends
+		if state.Phase == PhaseBet || state.Phase == PhaseOver {
+			for _, h := range state.Hands {
+				net += h.Net
+			}
+		}
+	}
+	if rounds != 20 || played == 0 || state.Error != "" {
+		t.Errorf("want 20 rounds played to the end, got %d rounds with %d moves (error %q)", rounds, played, state.Error)
+	}
+	if len(state.Dealer) < 2 || state.Hands[0].Outcome == "" {
+		t.Errorf("want the final round's settled hands, got %+v", state)
+	}
+	if state.Balance != 1000+net {
+		t.Errorf("want a balance of %d from the settled hands, got %d", 1000+net, state.Balance)
+	}
+	if len(commitments) < 2 || state.Commitment != "" || state.Revealed == nil {
+		t.Errorf("want a commitment to each shoe and the last one revealed, got %d commitments and %+v", len(commitments), state)
+	}
+}
+
+func TestReveal(t *testing.T) {
+	c, done := newClient(t, New())
+	defer done()
+	state, err := c.NewTable(blackjack.Options{Seed: 5}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.Options.SecureShuffle || state.Options.Seed != 0 {
+		t.Errorf("want a secure shuffle regardless of the seed asked for, got %+v", state.Options)
+	}
+	if state.Commitment == "" || state.Revealed != nil {
+		t.Fatalf("want a commitment to the shoe before it's dealt, got %+v", state)
+	}
+	if state, err = c.Bet(state.ID, 10); err != nil {
+		t.Fatal(err)
+	}
+	dealt := state.Hands[0].Cards
+	final, err := c.Leave(state.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := final.Revealed
+	if final.Phase != PhaseOver || final.Error != "" || r == nil || r.Commitment != state.Commitment || !r.Verify() {
+		t.Fatalf("want the shoe revealed matching its commitment when leaving, got %+v", final)
+	}
+	// the player's first card is the first dealt from the shoe
+	if r.Cards[0] != dealt[0] {
+		t.Errorf("want the shoe to start with the player's first card %s, got %s", dealt[0], r.Cards[0])
+	}
+	r.Cards[0], r.Cards[1] = r.Cards[1], r.Cards[0]
+	if r.Cards[0] != r.Cards[1] && r.Verify() {
+		t.Error("want a changed shoe not to match the commitment")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	s := New()
+	s.MaxTables = 1
+	c, done := newClient(t, s)
+	defer done()
+	tests := []struct {
+		name string
+		opts blackjack.Options
+		want string
+	}{
+		{"too many decks", blackjack.Options{Decks: DefaultMaxDecks + 1}, "number of decks"},
+		{"negative decks", blackjack.Options{Decks: -1}, "can't be negative"},
+		{"too many hands", blackjack.Options{Hands: DefaultMaxHands + 1}, "number of hands"},
+		{"negative minimum bet", blackjack.Options{MinBet: -10}, "minimum bet"},
+		{"payout too high", blackjack.Options{BlackjackPayout: 5}, "blackjack payout"},
+		{"payout under even money", blackjack.Options{BlackjackPayout: 0.5}, "blackjack payout"},
+		{"too many split hands", blackjack.Options{MaxSplitHands: 100}, "split hands"},
+	}
+	for _, tt := range tests {
+		if _, err := c.NewTable(tt.opts, 100); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: want an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+	state, err := c.NewTable(blackjack.Options{}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.NewTable(blackjack.Options{}, 100); err == nil || !strings.Contains(err.Error(), "too many tables") {
+		t.Errorf("want an error opening more than the most tables, got %v", err)
+	}
+	if _, err := c.Leave(state.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.NewTable(blackjack.Options{}, 100); err != nil {
+		t.Errorf("want a table once another has been left, got %v", err)
+	}
+
+	// a body larger than any request is cut off rather than read
+	body := `{"bankroll": 100, "options": {}` + strings.Repeat(" ", maxBodySize) + "}"
+	res, err := c.HTTPClient.Post(c.BaseURL+"/tables", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("want a bad request for a body over %d bytes, got %s", maxBodySize, res.Status)
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	s := New()
+	s.IdleTimeout = 50 * time.Millisecond
+	c, done := newClient(t, s)
+	defer done()
+	state, err := c.NewTable(blackjack.Options{}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// requests keep the table open past the timeout
+	for i := 0; i < 4; i++ {
+		time.Sleep(20 * time.Millisecond)
+		if _, err := c.State(state.ID); err != nil {
+			t.Fatalf("want the table kept open while it's used, got %v", err)
+		}
+	}
+	time.Sleep(150 * time.Millisecond)
+	if _, err := c.State(state.ID); err == nil || !strings.Contains(err.Error(), "no such table") {
+		t.Errorf("want an idle table to be closed, got %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.tables) != 0 {
+		t.Errorf("want no tables left, got %d", len(s.tables))
+	}
+}
+
+func TestErrors(t *testing.T) {
+	c, done := newClient(t, New())
+	defer done()
+	if _, err := c.NewTable(blackjack.Options{}, 0); err == nil {
+		t.Error("want an error for a table without a bankroll")
+	}
+	state, err := c.NewTable(blackjack.Options{MaxBet: 50, NoInsurance: true}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := state.ID
+	tests := []struct {
+		name string
+		do   func() (State, error)
+		want string
+	}{
+		{"move before betting", func() (State, error) { return c.Move(id, "hit") }, "waiting for bet"},
+		{"bet over the table maximum", func() (State, error) { return c.Bet(id, 60) }, "maximum bet"},
+		{"bet under the table minimum", func() (State, error) { return c.Bet(id, 0) }, "minimum bet"},
+		{"unknown table", func() (State, error) { return c.State("nope") }, "no such table"},
+	}
+	for _, tt := range tests {
+		if _, err := tt.do(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: want an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+	for state.Phase != PhasePlay {
+		if state, err = c.Bet(id, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.Move(id, "jump"); err == nil || !strings.Contains(err.Error(), "unknown move") {
+		t.Errorf("want an unknown move error, got %v", err)
+	}
+	if len(state.Moves) < 5 {
+		if _, err := c.Move(id, "surrender"); err == nil || !strings.Contains(err.Error(), "legal moves") {
+			t.Errorf("want an illegal move error, got %v", err)
+		}
+	}
+	if _, err := c.Leave(id); err != nil {
+		t.Fatal("unexpected error leaving:", err)
+	}
+	if _, err := c.State(id); err == nil {
+		t.Error("want an error getting a table which was left")
+	}
+}
+
+func TestSurrender(t *testing.T) {
+	c, done := newClient(t, New())
+	defer done()
+	state, err := c.NewTable(blackjack.Options{Surrender: blackjack.SurrenderEarly, Hands: 100}, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := state.ID
+	for state.Phase != PhaseOver {
+		switch state.Phase {
+		case PhaseBet:
+			state, err = c.Bet(id, 10)
+		case PhaseInsurance:
+			state, err = c.Insurance(id, false)
+		case PhasePlay:
+			state, err = c.Move(id, "stand")
+		case PhaseSurrender:
+			if len(state.Dealer) != 1 || len(state.Moves) != 0 {
+				t.Errorf("want just the upcard and no moves while offered early surrender, got %+v", state)
+			}
+			if state, err = c.Surrender(id, true); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if state.Phase != PhaseBet || state.Hands[0].Outcome != blackjack.OutcomeSurrender || state.Hands[0].Net != -5 {
+				t.Errorf("want the hand surrendered for half the bet, got %+v", state)
+			}
+			c.Leave(id)
+			return
+		}
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+	t.Error("want early surrender to be offered")
+}
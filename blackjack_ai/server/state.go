@@ -0,0 +1,118 @@
+// Package server exposes the blackjack engine over HTTP with JSON, one table per session, along with a
+// client for it.
+//
+// The API is:
+//
+//	POST   /tables                 create a table; the body is a NewTable
+//	GET    /tables/{id}            get the table's State
+//	POST   /tables/{id}/bet        place a bet; the body is a Bet
+//	POST   /tables/{id}/insurance  take or decline insurance; the body is an Insurance
+//	POST   /tables/{id}/surrender  take or decline early surrender; the body is a Surrender
+//	POST   /tables/{id}/move       make a move; the body is a Move
+//	DELETE /tables/{id}            leave the table
+//
+// Every request responds with the table's State once the game is waiting for the player's next decision,
+// or once it's over after leaving. Errors respond with an Error. The server shuffles every shoe itself
+// with deck.SecureShuffle, ignoring any seed in the options, and closes a table which gets no requests for
+// its idle timeout as if the player had left.
+//
+// So that the player can check the shoe isn't changed while it's dealt, the State has a commitment to the
+// order of the shoe being dealt, made with deck.Commit. Once the shoe is reshuffled, or the game is over,
+// the shoe is revealed for Reveal.Verify to check against the commitment.
+package server
+
+import (
+	"encoding/hex"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Phase is the decision a table is waiting for.
+type Phase string
+
+const (
+	PhaseBet       Phase = "bet"
+	PhaseInsurance Phase = "insurance"
+	PhaseSurrender Phase = "surrender" // early surrender, before the dealer checks for blackjack
+	PhasePlay      Phase = "play"
+	PhaseOver      Phase = "over" // the game has finished, or stopped because of an error
+)
+
+// NewTable is the body of a request to create a table.
+type NewTable struct {
+	Options  blackjack.Options `json:"options"`
+	Bankroll int               `json:"bankroll"`
+}
+
+// Bet is the body of a request to place a bet.
+type Bet struct {
+	Amount int `json:"amount"`
+}
+
+// Insurance is the body of a request to take or decline insurance.
+type Insurance struct {
+	Take bool `json:"take"`
+}
+
+// Surrender is the body of a request to take or decline early surrender.
+type Surrender struct {
+	Take bool `json:"take"`
+}
+
+// Move is the body of a request to make a move: hit, stand, double, split or surrender.
+type Move struct {
+	Move string `json:"move"`
+}
+
+// Error is the body of an error response.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// State is what a player can see at their table. The dealer's hole card is left out until it's revealed.
+type State struct {
+	ID      string            `json:"id"`
+	Phase   Phase             `json:"phase"`
+	Round   int               `json:"round"`
+	Balance int               `json:"balance"`
+	Options blackjack.Options `json:"options"`
+	Dealer  []deck.Card       `json:"dealer"`
+	Hands   []Hand            `json:"hands"`
+	Active  int               `json:"active"`          // the index of the hand being played
+	Moves   []string          `json:"moves,omitempty"` // the legal moves while playing
+	Error   string            `json:"error,omitempty"` // why the game stopped, if it was an error
+
+	Commitment string  `json:"commitment"`         // the commitment to the order of the shoe being dealt
+	Revealed   *Reveal `json:"revealed,omitempty"` // the last shoe to be finished with
+}
+
+// Reveal is a shoe which has been finished with, in the order it was dealt, along with the nonce of the
+// commitment to it.
+type Reveal struct {
+	Commitment string      `json:"commitment"`
+	Cards      []deck.Card `json:"cards"`
+	Nonce      string      `json:"nonce"` // in hex
+}
+
+// Verify returns true if the cards and the nonce match the commitment, so the shoe wasn't changed after the
+// commitment was published.
+func (r Reveal) Verify() bool {
+	c, err := deck.ParseCommitment(r.Commitment)
+	if err != nil {
+		return false
+	}
+	nonce, err := hex.DecodeString(r.Nonce)
+	if err != nil {
+		return false
+	}
+	return deck.Verify(c, r.Cards, nonce)
+}
+
+// Hand is one of the player's hands in the current, or just finished, round.
+type Hand struct {
+	Cards   []deck.Card       `json:"cards"`
+	Bet     int               `json:"bet"`
+	Outcome blackjack.Outcome `json:"outcome,omitempty"`
+	Net     int               `json:"net,omitempty"`
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"encoding/hex"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// decision is a player's answer to the question the table is waiting on.
+type decision struct {
+	bet       int
+	insurance bool
+	surrender bool
+	move      blackjack.Move
+	leave     bool
+}
+
+// table is a game being played in its own goroutine, with the player's seat played by the table
+// itself: each of its AI methods waits for the player's decision to arrive over HTTP.
+//
+// The game goroutine only runs between a decision being sent and the table signalling that it's
+// waiting again (or finished), while the request which sent the decision holds the table's lock, so
+// the state is never read and written at the same time.
+type table struct {
+	game      blackjack.Game
+	shoe      *deck.Shoe
+	committed []deck.Card // the order of the shoe when it was committed to
+	nonce     []byte      // of the commitment
+	state     State
+	decisions chan decision
+	waiting   chan struct{} // signalled whenever the game waits for a decision
+	done      chan struct{} // closed when the game is over
+	left      bool
+}
+
+func newTable(id string, opts blackjack.Options, bankroll int) *table {
+	opts.SecureShuffle = true // a seeded shoe would give away every card to anyone who knew the seed
+	t := &table{
+		game:      blackjack.New(opts),
+		decisions: make(chan decision),
+		waiting:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	t.shoe = t.game.NewShoe()
+	t.state = State{ID: id, Balance: bankroll, Options: t.game.Options()}
+	go func() {
+		_, err := t.game.PlayShoe(t.shoe, blackjack.Seat{AI: t, Bankroll: bankroll})
+		t.state.Phase = PhaseOver
+		t.state.Moves = nil
+		t.reveal()
+		if err != nil {
+			t.state.Error = err.Error()
+		}
+		close(t.done)
+	}()
+	return t
+}
+
+// commit commits to the order of the shoe, which has just been shuffled, after revealing the previous one.
+// Like deck.SecureShuffle, it panics if crypto/rand fails.
+func (t *table) commit() {
+	t.reveal()
+	cards := t.shoe.Cards()
+	c, nonce, err := deck.Commit(cards)
+	if err != nil {
+		panic(err)
+	}
+	t.state.Commitment = c.String()
+	t.committed = cards
+	t.nonce = nonce
+}
+
+// reveal reveals the shoe which has been committed to, if it hasn't been already.
+func (t *table) reveal() {
+	if t.nonce == nil {
+		return
+	}
+	t.state.Revealed = &Reveal{
+		Commitment: t.state.Commitment,
+		Cards:      t.committed,
+		Nonce:      hex.EncodeToString(t.nonce),
+	}
+	t.state.Commitment = ""
+	t.committed = nil
+	t.nonce = nil
+}
+
+// wait blocks until the game needs the player's next decision or is over.
+func (t *table) wait() {
+	select {
+	case <-t.waiting:
+	case <-t.done:
+	}
+}
+
+// decide sends the player's decision to the game and waits for it to play on to the next one.
+func (t *table) decide(d decision) {
+	t.decisions <- d
+	t.wait()
+}
+
+// leave makes the player leave the table, which stops the game. Leaving during a round stands on the hands
+// left to play and declines any offers, so the round is settled before the seat leaves.
+func (t *table) leave() {
+	select {
+	case <-t.done:
+	default:
+		t.decide(decision{leave: true})
+	}
+}
+
+// ask tells the player what the game is waiting for, then waits for their decision.
+func (t *table) ask(phase Phase) decision {
+	if t.left {
+		return decision{leave: true}
+	}
+	t.state.Phase = phase
+	t.waiting <- struct{}{}
+	d := <-t.decisions
+	if d.leave {
+		t.left = true
+	}
+	return d
+}
+
+func (t *table) Bet(shuffled bool) int {
+	d := t.ask(PhaseBet)
+	if d.leave {
+		return blackjack.LeaveTable
+	}
+	return d.bet
+}
+
+func (t *table) Insurance(hand []deck.Card) bool {
+	return t.ask(PhaseInsurance).insurance
+}
+
+func (t *table) Surrender(hand []deck.Card, dealer deck.Card) bool {
+	return t.ask(PhaseSurrender).surrender
+}
+
+func (t *table) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	t.state.Moves = t.game.LegalMoves()
+	d := t.ask(PhasePlay)
+	t.state.Moves = nil
+	if d.leave {
+		return blackjack.MoveStand
+	}
+	return d.move
+}
+
+func (t *table) Results(hand [][]deck.Card, dealer []deck.Card) {
+	// noop; the results are in the state from the settle events
+}
+
+// ObserveEvent implements blackjack.Observer, keeping the state up to date with the game.
+func (t *table) ObserveEvent(e blackjack.Event) {
+	s := &t.state
+	switch e.Type {
+	case blackjack.EventShuffle:
+		t.commit()
+	case blackjack.EventBet:
+		s.Round = e.Round
+		s.Dealer = nil
+		s.Hands = []Hand{{Bet: e.Amount}}
+		s.Active = 0
+	case blackjack.EventDeal, blackjack.EventReveal:
+		switch {
+		case e.Seat != blackjack.Dealer:
+			s.Hands[e.Hand].Cards = append(s.Hands[e.Hand].Cards, e.Card)
+			s.Active = e.Hand
+		case !e.Hole:
+			s.Dealer = append(s.Dealer, e.Card)
+		}
+	case blackjack.EventMove:
+		if e.Seat == blackjack.Dealer {
+			return
+		}
+		h := &s.Hands[e.Hand]
+		switch e.Move {
+		case "double":
+			h.Bet *= 2
+		case "split":
+			next := Hand{Cards: []deck.Card{h.Cards[1]}, Bet: h.Bet}
+			h.Cards = h.Cards[:1]
+			s.Hands = append(s.Hands, Hand{})
+			copy(s.Hands[e.Hand+2:], s.Hands[e.Hand+1:])
+			s.Hands[e.Hand+1] = next
+		}
+	case blackjack.EventSettle:
+		s.Balance += e.Amount
+		if e.Outcome != blackjack.OutcomeInsurance {
+			s.Hands[e.Hand].Outcome = e.Outcome
+			s.Hands[e.Hand].Net = e.Amount
+		}
+	}
+}
+
+// snapshot returns a copy of the state which won't change as the game plays on.
+func (t *table) snapshot() State {
+	s := t.state
+	s.Dealer = append([]deck.Card(nil), s.Dealer...)
+	s.Moves = append([]string(nil), s.Moves...)
+	s.Hands = make([]Hand, len(t.state.Hands))
+	for i, h := range t.state.Hands {
+		h.Cards = append([]deck.Card(nil), h.Cards...)
+		s.Hands[i] = h
+	}
+	return s
+}
@@ -0,0 +1,71 @@
+package sim
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteJSON writes the report as a JSON object.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// csvHeader is the header row written by WriteCSV, matching the JSON field names.
+var csvHeader = []string{
+	"seed", "sessions", "rounds", "hands", "wagered", "net",
+	"ev", "std_dev", "ci_low", "ci_high", "edge",
+	"win_rate", "loss_rate", "push_rate", "blackjack_rate", "surrender_rate",
+	"risk_of_ruin", "goal_rate",
+}
+
+// WriteCSV writes the report as a header row followed by a row of values. Pass header as false to
+// write only the values, e.g. to append another simulation's results to the same file.
+func (r Report) WriteCSV(w io.Writer, header bool) error {
+	cw := csv.NewWriter(w)
+	if header {
+		cw.Write(csvHeader)
+	}
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	cw.Write([]string{
+		strconv.FormatInt(r.Seed, 10), strconv.Itoa(r.Sessions), strconv.Itoa(r.Rounds), strconv.Itoa(r.Hands),
+		strconv.Itoa(r.Wagered), strconv.Itoa(r.Net),
+		f(r.EV), f(r.StdDev), f(r.CILow), f(r.CIHigh), f(r.Edge),
+		f(r.WinRate), f(r.LossRate), f(r.PushRate), f(r.BlackjackRate), f(r.SurrenderRate),
+		f(r.RiskOfRuin), f(r.GoalRate),
+	})
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteText writes the report in a form meant for people to read.
+func (r Report) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `Seed:          %d
+Sessions:      %d
+Rounds:        %d
+Hands:         %d
+Wagered:       %d
+Net:           %+d
+EV per round:  %+.4f (95%% CI %+.4f to %+.4f)
+Std dev:       %.4f
+Edge:          %+.3f%%
+Wins:          %.2f%%
+Losses:        %.2f%%
+Pushes:        %.2f%%
+Blackjacks:    %.2f%%
+Surrenders:    %.2f%%
+Risk of ruin:  %.2f%%
+Reached goal:  %.2f%%
+`,
+		r.Seed, r.Sessions, r.Rounds, r.Hands, r.Wagered, r.Net,
+		r.EV, r.CILow, r.CIHigh, r.StdDev, 100*r.Edge,
+		100*r.WinRate, 100*r.LossRate, 100*r.PushRate, 100*r.BlackjackRate, 100*r.SurrenderRate,
+		100*r.RiskOfRuin, 100*r.GoalRate)
+	return err
+}
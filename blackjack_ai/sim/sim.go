@@ -0,0 +1,229 @@
+// Package sim runs Monte Carlo simulations of blackjack, playing a large number of rounds across goroutines
+// to estimate how an AI performs under a set of table rules.
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+)
+
+// z95 is the number of standard errors either side of the mean covered by a 95% confidence interval.
+const z95 = 1.959964
+
+// Config describes a simulation.
+type Config struct {
+	// Options are the table rules. Options.Hands and Options.Seed are set for each session.
+	Options blackjack.Options
+	// AI returns the AI to play a session. Every session gets its own, since AIs keep state and
+	// sessions are played concurrently.
+	AI func() blackjack.AI
+	// Rounds is the total number of rounds to play.
+	Rounds int
+	// SessionRounds is the number of rounds in each session, which starts with a freshly shuffled shoe
+	// and the full bankroll; defaults to 1000.
+	SessionRounds int
+	// Unit is the size of a betting unit, which the report's EV and standard deviation are measured in;
+	// defaults to 1.
+	Unit int
+	// Bankroll is the balance each session starts with. A session is ruined if its balance ever falls to
+	// 0 or below; if Bankroll is 0 the risk of ruin isn't worked out.
+	Bankroll int
+	// Stop is when a session ends before SessionRounds, e.g. once it's broke or has reached a win goal.
+	Stop blackjack.Stop
+	// Seed is the seed for the first session's shoe, and each following session uses the next seed, skipping
+	// 0 which the engine would replace with a seed from the time, so the same seed replays the same
+	// simulation regardless of Workers. If 0, one is chosen from the time.
+	Seed int64
+	// Workers is the number of sessions played at once; defaults to the number of CPUs.
+	Workers int
+}
+
+// Report holds the statistics of a simulation. Wagered and Net are the totals bet and won, while EV and
+// the statistics which go with it are measured in betting units.
+type Report struct {
+	Seed     int64 `json:"seed"`
+	Sessions int   `json:"sessions"`
+	Rounds   int   `json:"rounds"`
+	Hands    int   `json:"hands"` // includes every hand created by splitting
+	Wagered  int   `json:"wagered"`
+	Net      int   `json:"net"`
+
+	EV     float64 `json:"ev"`      // the mean net winnings per round
+	StdDev float64 `json:"std_dev"` // of the net winnings per round
+	CILow  float64 `json:"ci_low"`  // the 95% confidence interval for EV
+	CIHigh float64 `json:"ci_high"`
+	Edge   float64 `json:"edge"` // net winnings as a fraction of the total wagered
+
+	WinRate       float64 `json:"win_rate"` // rates are per hand
+	LossRate      float64 `json:"loss_rate"`
+	PushRate      float64 `json:"push_rate"`
+	BlackjackRate float64 `json:"blackjack_rate"`
+	SurrenderRate float64 `json:"surrender_rate"`
+
+	RiskOfRuin float64 `json:"risk_of_ruin"` // the fraction of sessions ruined, or 0 without a bankroll
+	GoalRate   float64 `json:"goal_rate"`    // the fraction of sessions which reached Config.Stop.Goal
+}
+
+// Run plays the simulation and reports the results.
+func Run(cfg Config) (Report, error) {
+	if cfg.AI == nil {
+		return Report{}, errors.New("sim: no AI to play")
+	}
+	if cfg.Rounds <= 0 {
+		return Report{}, errors.New("sim: the number of rounds must be positive")
+	}
+	if cfg.SessionRounds <= 0 {
+		cfg.SessionRounds = 1000
+	}
+	if cfg.Unit <= 0 {
+		cfg.Unit = 1
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	sessions := (cfg.Rounds + cfg.SessionRounds - 1) / cfg.SessionRounds
+
+	jobs := make(chan int)
+	results := make(chan outcome)
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for session := range jobs {
+				results <- play(cfg, session)
+			}
+		}()
+	}
+	go func() {
+		for session := 0; session < sessions; session++ {
+			jobs <- session
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var total tally
+	var err error
+	for o := range results {
+		if o.err != nil {
+			if err == nil {
+				err = fmt.Errorf("sim: session %d: %w", o.session, o.err)
+			}
+			continue
+		}
+		total.add(o.result, cfg.Bankroll)
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	return total.report(cfg.Seed, cfg.Unit, cfg.Bankroll > 0), nil
+}
+
+// outcome is the result of a session, or the error which stopped it.
+type outcome struct {
+	session int
+	result  blackjack.Result
+	err     error
+}
+
+// play plays a single session with its own shoe.
+func play(cfg Config, session int) outcome {
+	opts := cfg.Options
+	opts.Seed = sessionSeed(cfg.Seed, session)
+	opts.Hands = cfg.SessionRounds
+	if rest := cfg.Rounds - session*cfg.SessionRounds; rest < opts.Hands {
+		opts.Hands = rest
+	}
+	game := blackjack.New(opts)
+	results, err := game.Play(blackjack.Seat{AI: cfg.AI(), Bankroll: cfg.Bankroll, Stop: cfg.Stop})
+	return outcome{session: session, result: results[0], err: err}
+}
+
+// sessionSeed returns the seed for a session's shoe: the next seed after the previous session's, other than 0.
+func sessionSeed(seed int64, session int) int64 {
+	next := seed + int64(session)
+	if seed < 0 && next >= 0 {
+		next++
+	}
+	return next
+}
+
+// tally sums the results of every session.
+type tally struct {
+	blackjack.Result
+	sessions int
+	ruined   int
+	goals    int
+}
+
+func (t *tally) add(r blackjack.Result, bankroll int) {
+	t.sessions++
+	if r.Low <= 0 || r.Stopped == blackjack.StopBroke {
+		t.ruined++
+	}
+	if r.Stopped == blackjack.StopGoal {
+		t.goals++
+	}
+	t.Balance += r.Balance - bankroll
+	t.Rounds += r.Rounds
+	t.Hands += r.Hands
+	t.Wins += r.Wins
+	t.Losses += r.Losses
+	t.Pushes += r.Pushes
+	t.Blackjacks += r.Blackjacks
+	t.Surrenders += r.Surrenders
+	t.Wagered += r.Wagered
+	t.SumSquares += r.SumSquares
+}
+
+func (t *tally) report(seed int64, unit int, ruin bool) Report {
+	r := Report{
+		Seed:     seed,
+		Sessions: t.sessions,
+		Rounds:   t.Rounds,
+		Hands:    t.Hands,
+		Wagered:  t.Wagered,
+		Net:      t.Balance,
+	}
+	if t.Rounds == 0 {
+		return r
+	}
+	n, u := float64(t.Rounds), float64(unit)
+	r.EV = float64(t.Balance) / n
+	if t.Rounds > 1 {
+		variance := (t.SumSquares - n*r.EV*r.EV) / (n - 1)
+		r.StdDev = math.Sqrt(math.Max(variance, 0)) / u
+	}
+	r.EV /= u
+	margin := z95 * r.StdDev / math.Sqrt(n)
+	r.CILow, r.CIHigh = r.EV-margin, r.EV+margin
+	if t.Wagered > 0 {
+		r.Edge = float64(t.Balance) / float64(t.Wagered)
+	}
+	if t.Hands > 0 {
+		hands := float64(t.Hands)
+		r.WinRate = float64(t.Wins) / hands
+		r.LossRate = float64(t.Losses) / hands
+		r.PushRate = float64(t.Pushes) / hands
+		r.BlackjackRate = float64(t.Blackjacks) / hands
+		r.SurrenderRate = float64(t.Surrenders) / hands
+	}
+	if ruin {
+		r.RiskOfRuin = float64(t.ruined) / float64(t.sessions)
+	}
+	if t.sessions > 0 {
+		r.GoalRate = float64(t.goals) / float64(t.sessions)
+	}
+	return r
+}
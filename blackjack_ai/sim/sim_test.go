@@ -0,0 +1,161 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+)
+
+// basicStrategy flat bets one unit of 10.
+func basicStrategy() blackjack.AI {
+	return blackjack.BasicStrategy(nil, blackjack.Options{}, blackjack.FlatBet(10))
+}
+
+func TestRunIsReproducible(t *testing.T) {
+	cfg := Config{
+		AI:            basicStrategy,
+		Rounds:        5000,
+		SessionRounds: 300,
+		Unit:          10,
+		Bankroll:      200,
+		Seed:          42,
+		Workers:       1,
+	}
+	want, err := Run(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Workers = 4
+	got, err := Run(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("want the same report with 1 or 4 workers, got\n%+v\n%+v", want, got)
+	}
+	if want.Rounds != 5000 || want.Sessions != 17 {
+		t.Errorf("want 5000 rounds in 17 sessions, got %d in %d", want.Rounds, want.Sessions)
+	}
+}
+
+func TestSessionSeeds(t *testing.T) {
+	// a negative seed counts up past 0, which the engine would replace with a seed from the time
+	cfg := Config{AI: basicStrategy, Rounds: 400, SessionRounds: 100, Seed: -2, Workers: 1}
+	want, err := Run(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Run(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("want the same report for the same negative seed, got\n%+v\n%+v", want, got)
+	}
+	var seeds []int64
+	for session := 0; session < 4; session++ {
+		seeds = append(seeds, sessionSeed(-2, session))
+	}
+	if fmt.Sprint(seeds) != "[-2 -1 1 2]" {
+		t.Errorf("want session seeds [-2 -1 1 2], got %v", seeds)
+	}
+}
+
+func TestRunStatistics(t *testing.T) {
+	r, err := Run(Config{AI: basicStrategy, Rounds: 20000, Unit: 10, Bankroll: 100, Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := r.WinRate + r.LossRate + r.PushRate; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("want win, loss and push rates to sum to 1, got %f", sum)
+	}
+	// flat bets of one unit with doubles and splits put the standard deviation of a round a little over a unit
+	if r.StdDev < 1 || r.StdDev > 1.3 {
+		t.Errorf("want a standard deviation of about 1.15, got %f", r.StdDev)
+	}
+	if r.CILow > r.EV || r.CIHigh < r.EV || r.CIHigh-r.CILow > 0.05 {
+		t.Errorf("want a narrow confidence interval around %f, got %f to %f", r.EV, r.CILow, r.CIHigh)
+	}
+	// basic strategy loses about half a percent, so a small bankroll is often lost over 1000 rounds
+	if r.RiskOfRuin == 0 || r.RiskOfRuin == 1 {
+		t.Errorf("want some but not every session ruined, got %f", r.RiskOfRuin)
+	}
+	if math.Abs(r.EV) > 0.05 {
+		t.Errorf("want EV close to 0, got %f", r.EV)
+	}
+}
+
+func TestRunStop(t *testing.T) {
+	r, err := Run(Config{
+		AI:       basicStrategy,
+		Rounds:   20000,
+		Bankroll: 100,
+		Stop:     blackjack.Stop{Broke: true, Goal: 150},
+		Seed:     8,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every session of 1000 rounds ends long before the last round, broke or at the goal
+	if r.RiskOfRuin+r.GoalRate < 0.99 || r.GoalRate == 0 || r.RiskOfRuin == 0 {
+		t.Errorf("want every session to go broke or reach the goal, got %f ruined and %f at the goal",
+			r.RiskOfRuin, r.GoalRate)
+	}
+	if r.Rounds >= 20000 {
+		t.Errorf("want sessions to stop early, got %d rounds", r.Rounds)
+	}
+}
+
+func TestTallyReport(t *testing.T) {
+	var tl tally
+	// rounds of +1, -1, +2 and -1 in one session, and a push in the next
+	tl.add(blackjack.Result{Balance: 101, Low: 99, Rounds: 4, Hands: 4, Wins: 2, Losses: 2, Wagered: 5, SumSquares: 7}, 100)
+	tl.add(blackjack.Result{Balance: 100, Low: 100, Rounds: 1, Hands: 1, Pushes: 1, Wagered: 1}, 100)
+	r := tl.report(1, 1, true)
+	if r.Net != 1 || r.EV != 0.2 || r.Edge != 1.0/6 {
+		t.Errorf("want net 1, EV 0.2 and edge 1/6, got %d, %f and %f", r.Net, r.EV, r.Edge)
+	}
+	if want := math.Sqrt((7 - 5*0.04) / 4); math.Abs(r.StdDev-want) > 1e-9 {
+		t.Errorf("want standard deviation %f, got %f", want, r.StdDev)
+	}
+	if r.RiskOfRuin != 0 || r.WinRate != 0.4 || r.PushRate != 0.2 {
+		t.Errorf("unexpected rates: %+v", r)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := Run(Config{Rounds: 10}); err == nil {
+		t.Error("want an error without an AI")
+	}
+	if _, err := Run(Config{AI: basicStrategy}); err == nil {
+		t.Error("want an error without any rounds")
+	}
+}
+
+func TestReportOutput(t *testing.T) {
+	r := Report{Seed: 3, Sessions: 1, Rounds: 10, EV: -0.5, RiskOfRuin: 0.25}
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded != r {
+		t.Errorf("JSON round trip: want %+v, got %+v (%v)", r, decoded, err)
+	}
+	buf.Reset()
+	if err := r.WriteCSV(&buf, true); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || len(records[0]) != len(records[1]) || records[1][6] != "-0.5" {
+		t.Errorf("unexpected CSV: %v", records)
+	}
+}
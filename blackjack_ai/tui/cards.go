@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+var suitSymbols = map[deck.Suit]string{
+	deck.Spade:   "♠",
+	deck.Diamond: "♦",
+	deck.Club:    "♣",
+	deck.Heart:   "♥",
+}
+
+// cardHeight is the number of lines each card is drawn with.
+const cardHeight = 5
+
+// cardLines draws a single card, or the back of one if hidden is true.
+func cardLines(c deck.Card, hidden bool) [cardHeight]string {
+	if hidden {
+		return [cardHeight]string{"+-----+", "|#####|", "|#####|", "|#####|", "+-----+"}
+	}
+	rank, _ := c.Rank.MarshalText()
+	r := string(rank)
+	suit := suitSymbols[c.Suit]
+	if c.Suit == deck.Joker {
+		r, suit = "JK", "*"
+	}
+	return [cardHeight]string{
+		"+-----+",
+		"|" + r + strings.Repeat(" ", 5-len(r)) + "|",
+		"|  " + suit + "  |",
+		"|" + strings.Repeat(" ", 5-len(r)) + r + "|",
+		"+-----+",
+	}
+}
+
+// drawCards draws cards side by side, with the cards at the given index and after drawn face down.
+// Pass a negative index to show every card.
+func drawCards(cards []deck.Card, hiddenFrom int) string {
+	var rows [cardHeight][]string
+	for i, c := range cards {
+		lines := cardLines(c, hiddenFrom >= 0 && i >= hiddenFrom)
+		for j, line := range lines {
+			rows[j] = append(rows[j], line)
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString(strings.Join(row, " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
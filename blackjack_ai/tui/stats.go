@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+)
+
+// DefaultBankroll is the bankroll a player starts with, or starts over with once they're broke.
+const DefaultBankroll = 1000
+
+// Stats is the player's bankroll and their record over every session, which is saved between sessions.
+type Stats struct {
+	Bankroll   int `json:"bankroll"`
+	Rounds     int `json:"rounds"`
+	Hands      int `json:"hands"`
+	Wins       int `json:"wins"`
+	Losses     int `json:"losses"`
+	Pushes     int `json:"pushes"`
+	Blackjacks int `json:"blackjacks"`
+	Surrenders int `json:"surrenders"`
+	Best       int `json:"best"` // the highest the bankroll has been
+}
+
+// LoadStats reads the stats saved at path. If there's no file yet, the stats start over with the
+// default bankroll.
+func LoadStats(path string) (Stats, error) {
+	stats := Stats{Bankroll: DefaultBankroll, Best: DefaultBankroll}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+	err = json.Unmarshal(b, &stats)
+	return stats, err
+}
+
+// Save writes the stats to path, replacing the file in one step so a crash can't leave it half written.
+func (s Stats) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,329 @@
+// Package tui plays blackjack full screen in a terminal, drawing the cards as ASCII art and keeping the
+// player's bankroll and stats between sessions.
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// clearScreen moves the cursor to the top left of the terminal and clears it.
+const clearScreen = "\033[H\033[2J"
+
+// moveKeys is the key for each move, and the label shown for it with the key in brackets.
+var moveKeys = []struct {
+	move, key, label string
+}{
+	{"hit", "h", "(h)it"},
+	{"stand", "s", "(s)tand"},
+	{"double", "d", "(d)ouble"},
+	{"split", "p", "s(p)lit"},
+	{"surrender", "r", "su(r)render"},
+}
+
+// UI is the player's seat at the table: an AI which asks the player for every decision, and an observer
+// which redraws the table as the game is played.
+type UI struct {
+	game  *blackjack.Game
+	in    *bufio.Scanner
+	out   io.Writer
+	stats *Stats
+	path  string
+
+	dealer  []deck.Card
+	hole    bool // the dealer's hole card is still face down
+	hands   []hand
+	active  int
+	message string
+	lastBet int
+	saveErr error
+}
+
+type hand struct {
+	cards   []deck.Card
+	bet     int
+	outcome blackjack.Outcome
+	net     int
+}
+
+// New returns a UI for playing the game, reading the player's input from in and drawing to out. The stats
+// are updated as the game is played and saved to path after every round.
+func New(g *blackjack.Game, in io.Reader, out io.Writer, stats *Stats, path string) *UI {
+	return &UI{
+		game:  g,
+		in:    bufio.NewScanner(in),
+		out:   out,
+		stats: stats,
+		path:  path,
+	}
+}
+
+// Run plays rounds until the player quits, runs out of money or has played every hand in the game's options.
+func (ui *UI) Run() error {
+	if ui.stats.Bankroll < ui.game.Options().MinBet {
+		ui.stats.Bankroll = DefaultBankroll
+		ui.message = fmt.Sprintf("You were out of money, so you're starting over with %d.", DefaultBankroll)
+	}
+	_, err := ui.game.Play(blackjack.Seat{AI: ui, Bankroll: ui.stats.Bankroll})
+	if err == nil {
+		err = ui.saveErr
+	}
+	if err == nil {
+		err = ui.stats.Save(ui.path)
+	}
+	return err
+}
+
+func (ui *UI) Bet(shuffled bool) int {
+	opts := ui.game.Options()
+	if shuffled && ui.message == "" {
+		ui.message = "The shoe was just shuffled."
+	}
+	for {
+		max := ui.stats.Bankroll
+		if opts.MaxBet > 0 && opts.MaxBet < max {
+			max = opts.MaxBet
+		}
+		if max < opts.MinBet {
+			ui.message = "You're out of money!"
+			ui.draw("")
+			return ui.leave()
+		}
+		if ui.lastBet < opts.MinBet || ui.lastBet > max {
+			ui.lastBet = opts.MinBet
+		}
+		input, ok := ui.ask(fmt.Sprintf("Bet %d to %d [%d], or (q)uit: ", opts.MinBet, max, ui.lastBet))
+		switch {
+		case !ok || input == "q":
+			return ui.leave()
+		case input == "":
+			return ui.lastBet
+		}
+		bet, err := strconv.Atoi(input)
+		if err != nil || bet < opts.MinBet || bet > max {
+			ui.message = fmt.Sprintf("Invalid bet: %s", input)
+			continue
+		}
+		ui.lastBet = bet
+		return bet
+	}
+}
+
+// leave leaves the table, which ends the game.
+func (ui *UI) leave() int {
+	return blackjack.LeaveTable
+}
+
+func (ui *UI) Insurance(hand []deck.Card) bool {
+	prompt := "The dealer shows an ace. Take insurance? (y)es, (n)o: "
+	if blackjack.Blackjack(hand...) {
+		prompt = "The dealer shows an ace. Take even money? (y)es, (n)o: "
+	}
+	for {
+		input, ok := ui.ask(prompt)
+		switch {
+		case !ok || input == "n":
+			return false
+		case input == "y":
+			return true
+		}
+		ui.message = fmt.Sprintf("Invalid option: %s", input)
+	}
+}
+
+func (ui *UI) Surrender(hand []deck.Card, dealer deck.Card) bool {
+	for {
+		input, ok := ui.ask("The dealer hasn't checked for blackjack yet. Surrender half your bet? (y)es, (n)o: ")
+		switch {
+		case !ok || input == "n":
+			return false
+		case input == "y":
+			return true
+		}
+		ui.message = fmt.Sprintf("Invalid option: %s", input)
+	}
+}
+
+func (ui *UI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	legal := ui.game.LegalMoves()
+	var labels []string
+	for _, mk := range moveKeys {
+		for _, m := range legal {
+			if m == mk.move {
+				labels = append(labels, mk.label)
+			}
+		}
+	}
+	for {
+		input, ok := ui.ask("What will you do? " + strings.Join(labels, ", ") + ": ")
+		if !ok {
+			return blackjack.MoveStand
+		}
+		for _, mk := range moveKeys {
+			for _, m := range legal {
+				if m == mk.move && input == mk.key {
+					move, _ := blackjack.MoveByName(m)
+					return move
+				}
+			}
+		}
+		ui.message = fmt.Sprintf("Invalid option: %s", input)
+	}
+}
+
+func (ui *UI) Results(hands [][]deck.Card, dealer []deck.Card) {
+	net := 0
+	for _, h := range ui.hands {
+		net += h.net
+	}
+	switch {
+	case net > 0:
+		ui.message = fmt.Sprintf("You won %d!", net)
+	case net < 0:
+		ui.message = fmt.Sprintf("You lost %d.", -net)
+	default:
+		ui.message = "Push."
+	}
+}
+
+// ObserveEvent implements blackjack.Observer, keeping the table and the stats up to date.
+func (ui *UI) ObserveEvent(e blackjack.Event) {
+	s := ui.stats
+	switch e.Type {
+	case blackjack.EventBet:
+		ui.dealer = nil
+		ui.hole = false
+		ui.hands = []hand{{bet: e.Amount}}
+		ui.active = 0
+	case blackjack.EventDeal, blackjack.EventReveal:
+		switch {
+		case e.Seat != blackjack.Dealer:
+			ui.hands[e.Hand].cards = append(ui.hands[e.Hand].cards, e.Card)
+			ui.active = e.Hand
+		case e.Hole:
+			ui.hole = true
+			ui.dealer = append(ui.dealer, deck.Card{})
+		case e.Type == blackjack.EventReveal:
+			ui.hole = false
+			ui.dealer[1] = e.Card
+		default:
+			ui.dealer = append(ui.dealer, e.Card)
+		}
+	case blackjack.EventMove:
+		if e.Seat == blackjack.Dealer {
+			return
+		}
+		h := &ui.hands[e.Hand]
+		switch e.Move {
+		case "double":
+			h.bet *= 2
+		case "split":
+			next := hand{cards: []deck.Card{h.cards[1]}, bet: h.bet}
+			h.cards = h.cards[:1]
+			ui.hands = append(ui.hands, hand{})
+			copy(ui.hands[e.Hand+2:], ui.hands[e.Hand+1:])
+			ui.hands[e.Hand+1] = next
+		}
+	case blackjack.EventSettle:
+		s.Bankroll += e.Amount
+		if s.Bankroll > s.Best {
+			s.Best = s.Bankroll
+		}
+		if e.Outcome == blackjack.OutcomeInsurance {
+			return
+		}
+		ui.hands[e.Hand].outcome = e.Outcome
+		ui.hands[e.Hand].net = e.Amount
+		s.Hands++
+		switch e.Outcome {
+		case blackjack.OutcomeWin:
+			s.Wins++
+		case blackjack.OutcomeBlackjack:
+			s.Wins++
+			s.Blackjacks++
+		case blackjack.OutcomeLoss:
+			s.Losses++
+		case blackjack.OutcomeSurrender:
+			s.Losses++
+			s.Surrenders++
+		case blackjack.OutcomePush:
+			s.Pushes++
+		}
+	case blackjack.EventRoundOver:
+		s.Rounds++
+		if err := s.Save(ui.path); err != nil && ui.saveErr == nil {
+			ui.saveErr = err
+		}
+	}
+}
+
+// ask redraws the table with the prompt and reads the player's answer, returning false once the input
+// has run out.
+func (ui *UI) ask(prompt string) (string, bool) {
+	ui.draw(prompt)
+	ui.message = ""
+	if !ui.in.Scan() {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(ui.in.Text())), true
+}
+
+// draw redraws the whole screen.
+func (ui *UI) draw(prompt string) {
+	var b strings.Builder
+	s := ui.stats
+	b.WriteString(clearScreen)
+	fmt.Fprintf(&b, "BLACKJACK    Bankroll: %d    Best: %d\n", s.Bankroll, s.Best)
+	fmt.Fprintf(&b, "Rounds: %d    Wins: %d    Losses: %d    Pushes: %d    Blackjacks: %d\n\n",
+		s.Rounds, s.Wins, s.Losses, s.Pushes, s.Blackjacks)
+	if len(ui.dealer) > 0 {
+		visible, hidden := ui.dealer, -1
+		if ui.hole {
+			visible, hidden = ui.dealer[:1], 1
+		}
+		fmt.Fprintf(&b, "Dealer: %s\n", score(visible))
+		b.WriteString(drawCards(ui.dealer, hidden))
+		b.WriteString("\n")
+	}
+	for i, h := range ui.hands {
+		if len(h.cards) == 0 {
+			continue
+		}
+		marker := "  "
+		if len(ui.hands) > 1 && i == ui.active && h.outcome == "" {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%sYou: %s    Bet: %d", marker, score(h.cards), h.bet)
+		if h.outcome != "" {
+			fmt.Fprintf(&b, "    %s %+d", h.outcome, h.net)
+		}
+		b.WriteString("\n")
+		b.WriteString(drawCards(h.cards, -1))
+		b.WriteString("\n")
+	}
+	if ui.message != "" {
+		b.WriteString(ui.message + "\n")
+	}
+	b.WriteString(prompt)
+	io.WriteString(ui.out, b.String())
+}
+
+// score describes a hand's score, e.g. "soft 17" or "blackjack".
+func score(cards []deck.Card) string {
+	switch s := blackjack.Score(cards...); {
+	case blackjack.Blackjack(cards...):
+		return "blackjack"
+	case s > 21:
+		return fmt.Sprintf("%d, bust", s)
+	case blackjack.Soft(cards...):
+		return fmt.Sprintf("soft %d", s)
+	default:
+		return strconv.Itoa(s)
+	}
+}
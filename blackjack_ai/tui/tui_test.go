@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/blackjack_ai/blackjack"
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "tui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestDrawCards(t *testing.T) {
+	cards, _ := deck.ParseHand("AS 10H")
+	want := `+-----+ +-----+
+|A    | |#####|
+|  ♠  | |#####|
+|    A| |#####|
++-----+ +-----+
+`
+	if got := drawCards(cards, 1); got != want {
+		t.Errorf("want\n%s\ngot\n%s", want, got)
+	}
+	if got := drawCards(cards, -1); !strings.Contains(got, "|10   |") || !strings.Contains(got, "|  ♥  |") {
+		t.Errorf("want the ten of hearts shown, got\n%s", got)
+	}
+}
+
+func TestStats(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stats.json")
+	stats, err := LoadStats(path)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if stats.Bankroll != DefaultBankroll {
+		t.Errorf("want a new player to start with %d, got %d", DefaultBankroll, stats.Bankroll)
+	}
+	stats.Bankroll, stats.Wins = 1234, 7
+	if err := stats.Save(path); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	loaded, err := LoadStats(path)
+	if err != nil || loaded != stats {
+		t.Errorf("want %+v, got %+v (%v)", stats, loaded, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stats.json")
+	stats := Stats{Bankroll: 100, Best: 100}
+	opts := blackjack.Options{Decks: 1, Hands: 1000, Seed: 3}
+	game := blackjack.New(opts)
+	// every prompt finds its answer within each cycle: bet 10, stand and decline insurance
+	in := strings.NewReader(strings.Repeat("10\ns\nn\n", 12) + "q\n")
+	var out bytes.Buffer
+	if err := New(&game, in, &out, &stats, path).Run(); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if stats.Rounds < 9 || stats.Wins+stats.Losses+stats.Pushes != stats.Hands {
+		t.Errorf("want at least 9 rounds with every hand counted, got %+v", stats)
+	}
+	saved, err := LoadStats(path)
+	if err != nil || saved != stats {
+		t.Errorf("want the stats saved, got %+v (%v)", saved, err)
+	}
+	for _, prompt := range strings.Split(out.String(), clearScreen) {
+		if strings.Contains(prompt, "What will you do?") && strings.Contains(prompt, "(d)ouble") {
+			continue
+		}
+		if strings.Contains(prompt, "What will you do?") {
+			t.Errorf("want doubling offered on the first decision of every hand, got\n%s", prompt)
+		}
+	}
+
+	// playing the same seed and decisions on the engine gives the same bankroll
+	game = blackjack.New(opts)
+	results, _ := game.Play(blackjack.Seat{AI: &standAI{rounds: stats.Rounds}, Bankroll: 100})
+	if results[0].Balance != stats.Bankroll {
+		t.Errorf("want a bankroll of %d, got %d", results[0].Balance, stats.Bankroll)
+	}
+}
+
+// standAI bets 10 and stands for the given number of rounds, then stops the game.
+type standAI struct {
+	rounds int
+}
+
+func (ai *standAI) Bet(shuffled bool) int {
+	if ai.rounds == 0 {
+		return 0
+	}
+	ai.rounds--
+	return 10
+}
+
+func (standAI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move { return blackjack.MoveStand }
+func (standAI) Insurance(hand []deck.Card) bool                        { return false }
+func (standAI) Surrender(hand []deck.Card, dealer deck.Card) bool      { return false }
+func (standAI) Results(hand [][]deck.Card, dealer []deck.Card)         {}
+
+func TestBrokePlayerStartsOver(t *testing.T) {
+	stats := Stats{Bankroll: 0, Best: 500}
+	game := blackjack.New(blackjack.Options{Seed: 1})
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	var out bytes.Buffer
+	if err := New(&game, strings.NewReader("q\n"), &out, &stats, filepath.Join(dir, "stats.json")).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Bankroll != DefaultBankroll || !strings.Contains(out.String(), "starting over") {
+		t.Errorf("want a broke player to start over with %d, got %d", DefaultBankroll, stats.Bankroll)
+	}
+}
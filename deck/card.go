@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
-	"time"
 )
 
 type Suit uint8
@@ -15,11 +14,10 @@ const (
 	Diamond
 	Club
 	Heart
-	Joker // this is a special case
+	Joker  // this is a special case
+	Trumps // the tarot trumps, numbered 1 to 21 by their rank
 )
 
-var suits = [...]Suit{Spade, Diamond, Club, Heart}
-
 type Rank uint8
 
 const (
@@ -37,11 +35,13 @@ const (
 	Jack
 	Queen
 	King
+	Knight // only in tarot decks, between the jack and queen
 )
 
 const (
-	minRank = Ace
-	maxRank = King
+	minRank  = Ace
+	maxRank  = Knight
+	maxTrump = 21
 )
 
 type Card struct {
@@ -53,20 +53,16 @@ func (c Card) String() string {
 	if c.Suit == Joker {
 		return c.Suit.String()
 	}
+	if c.Suit == Trumps {
+		return fmt.Sprintf("Trump %d", c.Rank)
+	}
 	return fmt.Sprintf("%s of %ss", c.Rank.String(), c.Suit.String())
 }
 
+// New builds a standard 52 card deck and applies the options to it. Other decks can be built with a
+// Definition, or by starting with the Using option.
 func New(opts ...func([]Card) []Card) []Card {
-	var cards []Card
-	for _, suit := range suits {
-		for rank := minRank; rank <= maxRank; rank++ {
-			cards = append(cards, Card{Suit: suit, Rank: rank})
-		}
-	}
-	for _, opt := range opts {
-		cards = opt(cards)
-	}
-	return cards
+	return French.New(opts...)
 }
 
 func DefaultSort(cards []Card) []Card {
@@ -81,7 +77,7 @@ func Less(cards []Card) func(i, j int) bool {
 }
 
 func absRank(c Card) int {
-	return int(c.Suit)*int(maxRank) + int(c.Rank)
+	return int(c.Suit)*(int(maxRank)+1) + int(c.Rank)
 }
 
 func Sort(less func(cards []Card) func(i, j int) bool) func([]Card) []Card {
@@ -91,11 +87,31 @@ func Sort(less func(cards []Card) func(i, j int) bool) func([]Card) []Card {
 	}
 }
 
-var shuffleRand = rand.New(rand.NewSource(time.Now().Unix()))
+var shuffleRand = rand.New(rand.NewSource(randomSeed()))
 
+// Shuffle shuffles the cards with math/rand, seeded from crypto/rand when the program starts. The seed can't
+// be guessed, but math/rand isn't cryptographically secure, so the order of later shuffles could be worked
+// out from the cards seen in earlier ones; use SecureShuffle when it mustn't be predicted.
 func Shuffle(cards []Card) []Card {
+	return shuffle(shuffleRand, cards)
+}
+
+// ShuffleWith returns an option which shuffles the cards with src, drawing from it on every use.
+func ShuffleWith(src rand.Source) func([]Card) []Card {
+	r := rand.New(src)
+	return func(cards []Card) []Card {
+		return shuffle(r, cards)
+	}
+}
+
+// Seed returns an option which shuffles the cards reproducibly: the same seed gives the same sequence of shuffles.
+func Seed(seed int64) func([]Card) []Card {
+	return ShuffleWith(rand.NewSource(seed))
+}
+
+func shuffle(r *rand.Rand, cards []Card) []Card {
 	ret := make([]Card, len(cards))
-	perm := shuffleRand.Perm(len(cards))
+	perm := r.Perm(len(cards))
 	for i, j := range perm {
 		ret[i] = cards[j]
 	}
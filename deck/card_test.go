@@ -93,3 +93,35 @@ func TestDeck(t *testing.T) {
 		t.Errorf("Expected %d cards, received %d cards", 13*4*3, len(cards))
 	}
 }
+
+func TestShuffleWith(t *testing.T) {
+	// first call to Perm(52) on a source seeded with 0 will return: [40 35 ...]
+	original := New()
+	cards := New(ShuffleWith(rand.NewSource(0)))
+	if cards[0] != original[40] {
+		t.Errorf("Expected first card to be %s, received %s.", original[40], cards[0])
+	}
+	if cards[1] != original[35] {
+		t.Errorf("Expected second card to be %s, received %s.", original[35], cards[1])
+	}
+}
+
+func TestSeed(t *testing.T) {
+	a := New(Deck(2), Seed(42))
+	b := New(Deck(2), Seed(42))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("Expected decks with the same seed to match, differed at %d: %s vs %s", i, a[i], b[i])
+		}
+	}
+	// reusing an option continues its sequence, so a replayed game reshuffles identically
+	opt1, opt2 := Seed(7), Seed(7)
+	New(opt1)
+	New(opt2)
+	c, d := New(opt1), New(opt2)
+	for i := range c {
+		if c[i] != d[i] {
+			t.Fatalf("Expected second shuffles with the same seed to match, differed at %d", i)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package deck
+
+// Comparator compares two cards for sorting, returning a negative number if a sorts before b, a positive
+// number if it sorts after, and 0 if the comparator can't tell them apart.
+type Comparator func(a, b Card) int
+
+// By returns a comparison for Sort which applies the comparators in turn until one tells the cards apart,
+// so By(BridgeSuits, AceHigh) sorts by suit first and By(AceHigh, BridgeSuits) by rank first.
+func By(cmps ...Comparator) func(cards []Card) func(i, j int) bool {
+	return func(cards []Card) func(i, j int) bool {
+		return func(i, j int) bool {
+			for _, cmp := range cmps {
+				if c := cmp(cards[i], cards[j]); c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		}
+	}
+}
+
+// AceLow orders cards by rank with aces below twos.
+func AceLow(a, b Card) int {
+	return int(a.Rank) - int(b.Rank)
+}
+
+// AceHigh orders cards by rank with aces above kings.
+func AceHigh(a, b Card) int {
+	return aceHigh(a.Rank) - aceHigh(b.Rank)
+}
+
+func aceHigh(r Rank) int {
+	if r == Ace {
+		return int(King) + 1
+	}
+	return int(r)
+}
+
+// SuitOrder orders cards by suit in the order given, with suits which aren't given after them all.
+func SuitOrder(suits ...Suit) Comparator {
+	order := make(map[Suit]int, len(suits))
+	for i, s := range suits {
+		order[s] = i
+	}
+	position := func(s Suit) int {
+		if i, ok := order[s]; ok {
+			return i
+		}
+		return len(suits) + int(s)
+	}
+	return func(a, b Card) int {
+		return position(a.Suit) - position(b.Suit)
+	}
+}
+
+var (
+	// DefaultSuits is the suit order of a new deck, and of DefaultSort.
+	DefaultSuits = SuitOrder(Spade, Diamond, Club, Heart)
+	// BridgeSuits is the order of suits in bridge, from clubs up to spades.
+	BridgeSuits = SuitOrder(Club, Diamond, Heart, Spade)
+)
+
+// Trump orders the trump suit above every other suit, e.g. By(Trump(Heart), BridgeSuits, AceHigh) for a
+// hand of spades with hearts as trumps. Cards of other suits are left for later comparators to order.
+func Trump(s Suit) Comparator {
+	return func(a, b Card) int {
+		return bool2int(a.Suit == s) - bool2int(b.Suit == s)
+	}
+}
+
+// JokersHigh orders jokers above every other card, and by their index amongst themselves. It should come
+// before any comparator which looks at ranks, since jokers don't have one.
+func JokersHigh(a, b Card) int {
+	if a.Suit == Joker && b.Suit == Joker {
+		return int(a.Rank) - int(b.Rank)
+	}
+	return bool2int(a.Suit == Joker) - bool2int(b.Suit == Joker)
+}
+
+// JokersLow orders jokers below every other card, and by their index amongst themselves.
+func JokersLow(a, b Card) int {
+	if a.Suit == Joker && b.Suit == Joker {
+		return int(a.Rank) - int(b.Rank)
+	}
+	return bool2int(b.Suit == Joker) - bool2int(a.Suit == Joker)
+}
+
+// Reverse reverses the order of a comparator, e.g. Reverse(AceHigh) to put high cards first.
+func Reverse(cmp Comparator) Comparator {
+	return func(a, b Card) int {
+		return cmp(b, a)
+	}
+}
+
+func bool2int(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package deck
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleBy() {
+	hand, _ := ParseHand("AS 2H KH 10C 3D JK 5S")
+	hand = Sort(By(JokersHigh, BridgeSuits, Reverse(AceHigh)))(hand)
+	fmt.Println(FormatHand(hand))
+
+	// Output:
+	// 10C 3D KH 2H AS 5S JK
+}
+
+func TestBy(t *testing.T) {
+	tests := []struct {
+		name string
+		less func(cards []Card) func(i, j int) bool
+		want string
+	}{
+		{"default", By(DefaultSuits, AceLow), "AS 5S 3D 10C 2H KH"},
+		{"rank first", By(AceHigh, BridgeSuits), "2H 3D 5S 10C KH AS"},
+		{"rank first ace low", By(AceLow, BridgeSuits), "AS 2H 3D 5S 10C KH"},
+		{"spades trumps", By(Trump(Spade), DefaultSuits, AceHigh), "3D 10C 2H KH 5S AS"},
+		{"hearts trumps", By(Trump(Heart), BridgeSuits, AceHigh), "10C 3D 5S AS 2H KH"},
+		{"high first", By(BridgeSuits, Reverse(AceHigh)), "10C 3D KH 2H AS 5S"},
+	}
+	for _, tt := range tests {
+		hand, _ := ParseHand("AS 2H KH 10C 3D 5S")
+		if got := FormatHand(Sort(tt.less)(hand)); got != tt.want {
+			t.Errorf("%s: want %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestByJokers(t *testing.T) {
+	hand, _ := ParseHand("JK1 KS JK AD")
+	if got := FormatHand(Sort(By(JokersHigh, DefaultSuits, AceLow))(hand)); got != "KS AD JK JK1" {
+		t.Errorf("jokers high: want KS AD JK JK1, got %s", got)
+	}
+	if got := FormatHand(Sort(By(JokersLow, DefaultSuits, AceLow))(hand)); got != "JK JK1 KS AD" {
+		t.Errorf("jokers low: want JK JK1 KS AD, got %s", got)
+	}
+}
+
+func TestByMatchesLess(t *testing.T) {
+	want := New(Shuffle, Jokers(2), DefaultSort)
+	got := New(Shuffle, Jokers(2), Sort(By(DefaultSuits, AceLow)))
+	if FormatHand(got) != FormatHand(want) {
+		t.Errorf("want By(DefaultSuits, AceLow) to sort like DefaultSort, got\n%s\n%s", FormatHand(want), FormatHand(got))
+	}
+}
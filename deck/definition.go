@@ -0,0 +1,308 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Definition describes a deck of cards by data: the suits, the ranks dealt in every suit, how many copies
+// of each card there are, and any cards outside the suits, like tarot trumps. Suits and ranks are still
+// identified by a card's Suit and Rank, but their names, short forms and order come from the definition,
+// so a deck's ranks can run ace high or put tens above kings, and its suits can be coins and cups.
+type Definition struct {
+	Name   string
+	Suits  []SuitDef
+	Ranks  []RankDef // from lowest to highest
+	Copies int       // of every suited card; defaults to 1
+	Extras []Extra   // dealt once each after the suited cards
+}
+
+// SuitDef names a suit in a definition.
+type SuitDef struct {
+	Suit  Suit
+	Name  string // e.g. "Spades"
+	Short string // used by Format and Parse, e.g. "S"
+}
+
+// RankDef names a rank in a definition.
+type RankDef struct {
+	Rank  Rank
+	Name  string // e.g. "Ace"
+	Short string // used by Format and Parse, e.g. "A"
+}
+
+// Extra is a card which doesn't belong to one of a definition's suits.
+type Extra struct {
+	Card  Card
+	Name  string
+	Short string
+}
+
+var (
+	frenchSuits = []SuitDef{{Spade, "Spades", "S"}, {Diamond, "Diamonds", "D"}, {Club, "Clubs", "C"}, {Heart, "Hearts", "H"}}
+	ace         = RankDef{Ace, "Ace", "A"}
+	numbers     = []RankDef{
+		{Two, "Two", "2"}, {Three, "Three", "3"}, {Four, "Four", "4"}, {Five, "Five", "5"}, {Six, "Six", "6"},
+		{Seven, "Seven", "7"}, {Eight, "Eight", "8"}, {Nine, "Nine", "9"}, {Ten, "Ten", "10"},
+	}
+	jack  = RankDef{Jack, "Jack", "J"}
+	queen = RankDef{Queen, "Queen", "Q"}
+	king  = RankDef{King, "King", "K"}
+)
+
+// join concatenates lists of ranks.
+func join(lists ...[]RankDef) []RankDef {
+	var ranks []RankDef
+	for _, l := range lists {
+		ranks = append(ranks, l...)
+	}
+	return ranks
+}
+
+var (
+	// French is the standard 52 card deck built by New, ace low.
+	French = Definition{
+		Name:  "French",
+		Suits: frenchSuits,
+		Ranks: join([]RankDef{ace}, numbers, []RankDef{jack, queen, king}),
+	}
+	// Piquet is the 32 card deck of sevens up to aces, ace high, also used for belote and skat.
+	Piquet = Definition{
+		Name:  "Piquet",
+		Suits: frenchSuits,
+		Ranks: join(numbers[5:], []RankDef{jack, queen, king, ace}),
+	}
+	// Pinochle is the 48 card deck of two copies of nines up to aces, with tens ranked above kings.
+	Pinochle = Definition{
+		Name:   "Pinochle",
+		Suits:  frenchSuits,
+		Ranks:  []RankDef{numbers[7], jack, queen, king, numbers[8], ace},
+		Copies: 2,
+	}
+	// Euchre is the 24 card deck of nines up to aces, ace high.
+	Euchre = Definition{
+		Name:  "Euchre",
+		Suits: frenchSuits,
+		Ranks: join(numbers[7:], []RankDef{jack, queen, king, ace}),
+	}
+	// Spanish40 is the 40 card Spanish deck, without eights and nines. Coins, cups, swords and batons use
+	// the diamond, heart, spade and club suits, and the knave, knight and king, numbered 10 to 12 in the
+	// deck, use the jack, queen and king ranks.
+	Spanish40 = Definition{
+		Name:  "Spanish 40",
+		Suits: spanishSuits,
+		Ranks: join(spanishPips[:7], spanishFaces),
+	}
+	// Spanish48 is the 48 card Spanish deck, with eights and nines.
+	Spanish48 = Definition{
+		Name:  "Spanish 48",
+		Suits: spanishSuits,
+		Ranks: join(spanishPips, spanishFaces),
+	}
+	// Tarot is the 78 card French tarot deck: four suits of fourteen, with a knight between the jack and
+	// queen, the 21 trumps and the excuse.
+	Tarot = Definition{
+		Name:   "Tarot",
+		Suits:  []SuitDef{{Spade, "Spades", "S"}, {Heart, "Hearts", "H"}, {Diamond, "Diamonds", "D"}, {Club, "Clubs", "C"}},
+		Ranks:  join([]RankDef{ace}, numbers, []RankDef{jack, {Knight, "Knight", "C"}, queen, king}),
+		Extras: tarotExtras(),
+	}
+)
+
+var (
+	spanishSuits = []SuitDef{{Diamond, "Coins", "O"}, {Heart, "Cups", "C"}, {Spade, "Swords", "E"}, {Club, "Batons", "B"}}
+	spanishPips  = []RankDef{
+		{Ace, "One", "1"}, {Two, "Two", "2"}, {Three, "Three", "3"}, {Four, "Four", "4"}, {Five, "Five", "5"},
+		{Six, "Six", "6"}, {Seven, "Seven", "7"}, {Eight, "Eight", "8"}, {Nine, "Nine", "9"},
+	}
+	spanishFaces = []RankDef{{Jack, "Knave", "10"}, {Queen, "Knight", "11"}, {King, "King", "12"}}
+)
+
+func tarotExtras() []Extra {
+	extras := make([]Extra, 0, 22)
+	for i := 1; i <= maxTrump; i++ {
+		extras = append(extras, Extra{
+			Card:  Card{Suit: Trumps, Rank: Rank(i)},
+			Name:  fmt.Sprintf("Trump %d", i),
+			Short: fmt.Sprintf("%dT", i),
+		})
+	}
+	return append(extras, Extra{Card: Card{Suit: Joker}, Name: "Excuse", Short: "EX"})
+}
+
+// New builds the deck the definition describes, sorted by suit and then rank, with any extras last, and
+// applies the options to it as New does.
+func (d Definition) New(opts ...func([]Card) []Card) []Card {
+	copies := d.Copies
+	if copies < 1 {
+		copies = 1
+	}
+	cards := make([]Card, 0, copies*len(d.Suits)*len(d.Ranks)+len(d.Extras))
+	for _, s := range d.Suits {
+		for _, r := range d.Ranks {
+			for i := 0; i < copies; i++ {
+				cards = append(cards, Card{Suit: s.Suit, Rank: r.Rank})
+			}
+		}
+	}
+	for _, e := range d.Extras {
+		cards = append(cards, e.Card)
+	}
+	for _, opt := range opts {
+		cards = opt(cards)
+	}
+	return cards
+}
+
+// Using returns an option which replaces the cards with the deck the definition describes, so it has to
+// be the first option, e.g. New(Using(Euchre), Deck(2), Shuffle). It lets shoes deal other decks.
+func Using(d Definition) func([]Card) []Card {
+	return func([]Card) []Card {
+		return d.New()
+	}
+}
+
+// Validate returns an error if the definition has no cards, or repeats a suit, rank, extra or short form.
+func (d Definition) Validate() error {
+	if len(d.Extras) == 0 && (len(d.Suits) == 0 || len(d.Ranks) == 0) {
+		return fmt.Errorf("deck: definition %q has no cards", d.Name)
+	}
+	suits := make(map[Suit]bool)
+	ranks := make(map[Rank]bool)
+	cards := make(map[Card]bool)
+	shorts := make(map[string]bool)
+	short := func(s string) error {
+		s = strings.ToUpper(s)
+		if s == "" || shorts[s] {
+			return fmt.Errorf("deck: definition %q has a missing or repeated short form %q", d.Name, s)
+		}
+		shorts[s] = true
+		return nil
+	}
+	for _, s := range d.Suits {
+		if suits[s.Suit] {
+			return fmt.Errorf("deck: definition %q repeats the suit %s", d.Name, s.Name)
+		}
+		suits[s.Suit] = true
+		if err := short(s.Short); err != nil {
+			return err
+		}
+	}
+	rankShorts := make(map[string]bool)
+	for _, r := range d.Ranks {
+		if ranks[r.Rank] || rankShorts[strings.ToUpper(r.Short)] || r.Short == "" {
+			return fmt.Errorf("deck: definition %q repeats the rank %s", d.Name, r.Name)
+		}
+		ranks[r.Rank] = true
+		rankShorts[strings.ToUpper(r.Short)] = true
+	}
+	for _, e := range d.Extras {
+		if cards[e.Card] || (suits[e.Card.Suit] && ranks[e.Card.Rank]) {
+			return fmt.Errorf("deck: definition %q repeats the card %s", d.Name, e.Name)
+		}
+		cards[e.Card] = true
+		if err := short(e.Short); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// suit returns the position of the card's suit in the definition, or -1.
+func (d Definition) suit(c Card) int {
+	for i, s := range d.Suits {
+		if s.Suit == c.Suit {
+			return i
+		}
+	}
+	return -1
+}
+
+// rank returns the position of the card's rank in the definition, or -1.
+func (d Definition) rank(c Card) int {
+	for i, r := range d.Ranks {
+		if r.Rank == c.Rank {
+			return i
+		}
+	}
+	return -1
+}
+
+// extra returns the position of the card in the definition's extras, or -1.
+func (d Definition) extra(c Card) int {
+	for i, e := range d.Extras {
+		if e.Card == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// Position returns the card's place in a sorted deck of the definition, ignoring copies, or -1 if the
+// definition doesn't have the card.
+func (d Definition) Position(c Card) int {
+	if i := d.extra(c); i >= 0 {
+		return len(d.Suits)*len(d.Ranks) + i
+	}
+	s, r := d.suit(c), d.rank(c)
+	if s < 0 || r < 0 {
+		return -1
+	}
+	return s*len(d.Ranks) + r
+}
+
+// Less sorts cards the way the definition builds them: by suit, then rank, with extras last. It can be
+// used with Sort.
+func (d Definition) Less(cards []Card) func(i, j int) bool {
+	return func(i, j int) bool {
+		return d.Position(cards[i]) < d.Position(cards[j])
+	}
+}
+
+// CardName returns the card's name in the definition, e.g. "Knight of Cups", falling back to
+// Card.String for cards the definition doesn't have.
+func (d Definition) CardName(c Card) string {
+	if i := d.extra(c); i >= 0 {
+		return d.Extras[i].Name
+	}
+	s, r := d.suit(c), d.rank(c)
+	if s < 0 || r < 0 {
+		return c.String()
+	}
+	return d.Ranks[r].Name + " of " + d.Suits[s].Name
+}
+
+// Format returns the card's short form in the definition: the rank's followed by the suit's, e.g. "11C",
+// or the extra's. It returns "?" for cards the definition doesn't have.
+func (d Definition) Format(c Card) string {
+	if i := d.extra(c); i >= 0 {
+		return d.Extras[i].Short
+	}
+	s, r := d.suit(c), d.rank(c)
+	if s < 0 || r < 0 {
+		return "?"
+	}
+	return d.Ranks[r].Short + d.Suits[s].Short
+}
+
+// Parse parses a card from its short form in the definition. Parsing is case-insensitive.
+func (d Definition) Parse(str string) (Card, error) {
+	up := strings.ToUpper(strings.TrimSpace(str))
+	for _, e := range d.Extras {
+		if up == strings.ToUpper(e.Short) {
+			return e.Card, nil
+		}
+	}
+	for _, s := range d.Suits {
+		suit := strings.ToUpper(s.Short)
+		if !strings.HasSuffix(up, suit) {
+			continue
+		}
+		for _, r := range d.Ranks {
+			if up[:len(up)-len(suit)] == strings.ToUpper(r.Short) {
+				return Card{Suit: s.Suit, Rank: r.Rank}, nil
+			}
+		}
+	}
+	return Card{}, fmt.Errorf("deck: invalid %s card %q", d.Name, str)
+}
@@ -0,0 +1,128 @@
+package deck
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ExampleDefinition_CardName() {
+	fmt.Println(Spanish40.CardName(Card{Rank: Queen, Suit: Heart}))
+	fmt.Println(Tarot.CardName(Card{Rank: Knight, Suit: Club}))
+	fmt.Println(Tarot.CardName(Card{Suit: Joker}))
+
+	// Output:
+	// Knight of Cups
+	// Knight of Clubs
+	// Excuse
+}
+
+func TestDefinitions(t *testing.T) {
+	tests := []struct {
+		def  Definition
+		size int
+	}{
+		{French, 52},
+		{Piquet, 32},
+		{Pinochle, 48},
+		{Euchre, 24},
+		{Spanish40, 40},
+		{Spanish48, 48},
+		{Tarot, 78},
+	}
+	for _, tt := range tests {
+		if err := tt.def.Validate(); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.def.Name, err)
+		}
+		cards := tt.def.New()
+		if len(cards) != tt.size {
+			t.Errorf("%s: want %d cards, got %d", tt.def.Name, tt.size, len(cards))
+		}
+		for _, c := range cards {
+			got, err := tt.def.Parse(tt.def.Format(c))
+			if err != nil || got != c {
+				t.Errorf("%s: %s didn't round trip through %q, got %v, %v", tt.def.Name, tt.def.CardName(c),
+					tt.def.Format(c), got, err)
+			}
+		}
+		shuffled := New(Using(tt.def), Shuffle, Sort(tt.def.Less))
+		if !reflect.DeepEqual(shuffled, cards) {
+			t.Errorf("%s: want sorting to restore the order it was built in", tt.def.Name)
+		}
+	}
+}
+
+func TestDefinitionOrder(t *testing.T) {
+	// piquet ranks aces high, pinochle puts tens above kings
+	if Piquet.Position(Card{Rank: Ace, Suit: Spade}) <= Piquet.Position(Card{Rank: King, Suit: Spade}) {
+		t.Error("piquet: want the ace above the king")
+	}
+	if Pinochle.Position(Card{Rank: Ten, Suit: Heart}) <= Pinochle.Position(Card{Rank: King, Suit: Heart}) {
+		t.Error("pinochle: want the ten above the king")
+	}
+	if Euchre.Position(Card{Rank: Two, Suit: Heart}) != -1 {
+		t.Error("euchre: want no twos")
+	}
+	if got := Euchre.CardName(Card{Rank: Two, Suit: Heart}); got != "Two of Hearts" {
+		t.Errorf("want cards outside the definition named by Card.String, got %q", got)
+	}
+}
+
+func TestDefinitionOptions(t *testing.T) {
+	if !reflect.DeepEqual(New(), French.New()) {
+		t.Error("want New to build the French deck")
+	}
+	cards := New(Using(Pinochle), Deck(2), Filter(func(c Card) bool { return c.Rank == Nine }))
+	if len(cards) != 2*(48-8) {
+		t.Errorf("want %d cards, got %d", 2*(48-8), len(cards))
+	}
+	shoe := NewShoe(1, Using(Euchre), Seed(1))
+	if shoe.Size() != 24 {
+		t.Errorf("want a shoe of 24 cards, got %d", shoe.Size())
+	}
+}
+
+func TestDefinitionInvalid(t *testing.T) {
+	tests := map[string]Definition{
+		"empty":          {Name: "empty"},
+		"repeated suit":  {Suits: []SuitDef{{Spade, "Spades", "S"}, {Spade, "Spades", "X"}}, Ranks: French.Ranks},
+		"repeated rank":  {Suits: frenchSuits, Ranks: []RankDef{ace, ace}},
+		"repeated short": {Suits: []SuitDef{{Spade, "Spades", "S"}, {Heart, "Hearts", "S"}}, Ranks: French.Ranks},
+		"suited extra":   {Suits: frenchSuits, Ranks: French.Ranks, Extras: []Extra{{Card{Spade, Ace}, "Ace", "X"}}},
+	}
+	for name, def := range tests {
+		if err := def.Validate(); err == nil {
+			t.Errorf("%s: want an error, got nil", name)
+		}
+	}
+	if _, err := Euchre.Parse("2H"); err == nil {
+		t.Error("want an error parsing a two in a euchre deck, got nil")
+	}
+}
+
+func TestTarotCards(t *testing.T) {
+	cards := Tarot.New()
+	seen := make(map[int]Card)
+	for _, c := range cards {
+		if str := c.String(); strings.Contains(str, "(") {
+			t.Errorf("Expected %#v to have a name, received %q.", c, str)
+		}
+		var got Card
+		if err := got.UnmarshalText([]byte(FormatHand([]Card{c}))); err != nil || got != c {
+			t.Errorf("Expected %#v to round trip through its short form, received %#v (%v).", c, got, err)
+		}
+		if other, ok := seen[absRank(c)]; ok {
+			t.Errorf("Expected %s and %s to sort apart.", c, other)
+		}
+		seen[absRank(c)] = c
+	}
+	hand, err := ParseHand("CS 21T")
+	expected := []Card{{Rank: Knight, Suit: Spade}, {Rank: 21, Suit: Trumps}}
+	if err != nil || !reflect.DeepEqual(hand, expected) {
+		t.Errorf("Expected %v, received %v (%v).", expected, hand, err)
+	}
+	if str := fmt.Sprint(hand); str != "[Knight of Spades Trump 21]" {
+		t.Errorf("Expected [Knight of Spades Trump 21], received %s.", str)
+	}
+}
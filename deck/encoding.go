@@ -0,0 +1,156 @@
+package deck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var suitShort = [...]string{Spade: "S", Diamond: "D", Club: "C", Heart: "H", Joker: "JK", Trumps: "T"}
+
+var rankShort = [...]string{Ace: "A", Two: "2", Three: "3", Four: "4", Five: "5", Six: "6", Seven: "7", Eight: "8", Nine: "9", Ten: "10", Jack: "J", Queen: "Q", King: "K", Knight: "C"}
+
+// MarshalText encodes the suit as its short form, e.g. "S" for spades.
+func (s Suit) MarshalText() ([]byte, error) {
+	if int(s) >= len(suitShort) {
+		return nil, fmt.Errorf("deck: invalid suit %d", s)
+	}
+	return []byte(suitShort[s]), nil
+}
+
+// UnmarshalText decodes a suit from its short form ("S") or its name ("Spade", "spades").
+func (s *Suit) UnmarshalText(text []byte) error {
+	str := strings.ToLower(string(text))
+	for i := range suitShort {
+		name := strings.ToLower(Suit(i).String())
+		if str == strings.ToLower(suitShort[i]) || str == name || str == name+"s" {
+			*s = Suit(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("deck: invalid suit %q", text)
+}
+
+// MarshalText encodes the rank as its short form, e.g. "A" for an ace or "10" for a ten.
+func (r Rank) MarshalText() ([]byte, error) {
+	if r < minRank || r > maxRank {
+		return nil, fmt.Errorf("deck: invalid rank %d", r)
+	}
+	return []byte(rankShort[r]), nil
+}
+
+// UnmarshalText decodes a rank from its short form ("A", "10", "T", "C") or its name ("Ace").
+func (r *Rank) UnmarshalText(text []byte) error {
+	str := strings.ToLower(string(text))
+	if str == "t" {
+		*r = Ten
+		return nil
+	}
+	for rank := minRank; rank <= maxRank; rank++ {
+		if str == strings.ToLower(rankShort[rank]) || str == strings.ToLower(rank.String()) {
+			*r = rank
+			return nil
+		}
+	}
+	return fmt.Errorf("deck: invalid rank %q", text)
+}
+
+// MarshalText encodes the card as its rank followed by its suit, e.g. "AS" or "10H". Jokers are
+// encoded as "JK", followed by their index when it isn't 0 (e.g. "JK1"), and tarot trumps as their
+// number followed by "T" (e.g. "21T").
+func (c Card) MarshalText() ([]byte, error) {
+	if c.Suit == Joker {
+		if c.Rank == 0 {
+			return []byte(suitShort[Joker]), nil
+		}
+		return []byte(suitShort[Joker] + strconv.Itoa(int(c.Rank))), nil
+	}
+	if c.Suit == Trumps {
+		if c.Rank < 1 || c.Rank > maxTrump {
+			return nil, fmt.Errorf("deck: invalid trump %d", c.Rank)
+		}
+		return []byte(strconv.Itoa(int(c.Rank)) + suitShort[Trumps]), nil
+	}
+	rank, err := c.Rank.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	suit, err := c.Suit.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return append(rank, suit...), nil
+}
+
+// UnmarshalText decodes a card from the short form produced by MarshalText. Parsing is case-insensitive.
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// ParseCard parses a card in its short form, e.g. "AS", "10h", "JK" or "21T".
+func ParseCard(s string) (Card, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	if strings.HasPrefix(str, suitShort[Joker]) {
+		if str == suitShort[Joker] {
+			return Card{Suit: Joker}, nil
+		}
+		i, err := strconv.Atoi(str[len(suitShort[Joker]):])
+		if err != nil || i < 0 || i > 255 {
+			return Card{}, fmt.Errorf("deck: invalid card %q", s)
+		}
+		return Card{Suit: Joker, Rank: Rank(i)}, nil
+	}
+	if strings.HasSuffix(str, suitShort[Trumps]) {
+		if i, err := strconv.Atoi(str[:len(str)-len(suitShort[Trumps])]); err == nil {
+			if i < 1 || i > maxTrump {
+				return Card{}, fmt.Errorf("deck: invalid card %q", s)
+			}
+			return Card{Suit: Trumps, Rank: Rank(i)}, nil
+		}
+	}
+	if len(str) < 2 {
+		return Card{}, fmt.Errorf("deck: invalid card %q", s)
+	}
+	var c Card
+	if err := c.Rank.UnmarshalText([]byte(str[:len(str)-1])); err != nil {
+		return Card{}, fmt.Errorf("deck: invalid card %q: %v", s, err)
+	}
+	if err := c.Suit.UnmarshalText([]byte(str[len(str)-1:])); err != nil || c.Suit == Joker || c.Suit == Trumps {
+		return Card{}, fmt.Errorf("deck: invalid card %q: invalid suit", s)
+	}
+	return c, nil
+}
+
+// ParseHand parses a list of cards in their short form separated by spaces and/or commas, e.g. "AS KD".
+func ParseHand(s string) ([]Card, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	cards := make([]Card, 0, len(fields))
+	for _, f := range fields {
+		c, err := ParseCard(f)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
+
+// FormatHand returns the short forms of the cards separated by spaces; the result can be read back with ParseHand.
+func FormatHand(cards []Card) string {
+	strs := make([]string, len(cards))
+	for i, c := range cards {
+		text, err := c.MarshalText()
+		if err != nil {
+			text = []byte("?")
+		}
+		strs[i] = string(text)
+	}
+	return strings.Join(strs, " ")
+}
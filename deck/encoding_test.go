@@ -0,0 +1,69 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func ExampleParseHand() {
+	hand, _ := ParseHand("AS 10h, kd JK")
+	fmt.Println(hand)
+	fmt.Println(FormatHand(hand))
+
+	// Output:
+	// [Ace of Spades Ten of Hearts King of Diamonds Joker]
+	// AS 10H KD JK
+}
+
+func TestCardTextRoundTrip(t *testing.T) {
+	for _, c := range New(Jokers(3)) {
+		text, err := c.MarshalText()
+		if err != nil {
+			t.Fatalf("Unexpected error marshaling %s: %v", c, err)
+		}
+		var got Card
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("Unexpected error unmarshaling %q: %v", text, err)
+		}
+		if got != c {
+			t.Errorf("Expected %q to decode to %#v, received %#v.", text, c, got)
+		}
+	}
+}
+
+func TestParseCardInvalid(t *testing.T) {
+	for _, s := range []string{"", "A", "1S", "11H", "AX", "AJK", "JKX", "0T", "22T", "TT"} {
+		if _, err := ParseCard(s); err == nil {
+			t.Errorf("Expected an error parsing %q.", s)
+		}
+	}
+}
+
+func TestCardJSON(t *testing.T) {
+	type fixture struct {
+		Hand []Card
+		Suit Suit
+		Rank Rank
+	}
+	in := fixture{
+		Hand: []Card{{Rank: Ace, Suit: Spade}, {Rank: Ten, Suit: Heart}},
+		Suit: Club,
+		Rank: Queen,
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal("Unexpected error marshaling JSON:", err)
+	}
+	expected := `{"Hand":["AS","10H"],"Suit":"C","Rank":"Q"}`
+	if string(b) != expected {
+		t.Errorf("Expected %s, received %s.", expected, b)
+	}
+	var out fixture
+	if err := json.Unmarshal([]byte(`{"Hand":["AS","10H"],"Suit":"clubs","Rank":"Queen"}`), &out); err != nil {
+		t.Fatal("Unexpected error unmarshaling JSON:", err)
+	}
+	if out.Hand[0] != in.Hand[0] || out.Hand[1] != in.Hand[1] || out.Suit != in.Suit || out.Rank != in.Rank {
+		t.Errorf("Expected %v, received %v.", in, out)
+	}
+}
@@ -0,0 +1,111 @@
+package deck
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+// SecureShuffle shuffles the cards with a Fisher–Yates shuffle driven by crypto/rand, so unlike Shuffle
+// the order can't be worked out from the cards of earlier shuffles. It panics if crypto/rand fails, which
+// only happens when the operating system can't provide randomness.
+func SecureShuffle(cards []Card) []Card {
+	ret, err := secureShuffle(rand.Reader, cards)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+func secureShuffle(r io.Reader, cards []Card) ([]Card, error) {
+	ret := make([]Card, len(cards))
+	copy(ret, cards)
+	for i := len(ret) - 1; i > 0; i-- {
+		j, err := uniform(r, uint64(i+1))
+		if err != nil {
+			return nil, fmt.Errorf("deck: reading randomness: %w", err)
+		}
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	return ret, nil
+}
+
+// randomSeed returns a seed for math/rand read from crypto/rand, falling back to the time if the operating
+// system can't provide randomness.
+func randomSeed() int64 {
+	var b [8]byte
+	if _, err := io.ReadFull(rand.Reader, b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.BigEndian.Uint64(b[:]))
+}
+
+// uniform returns a random number in [0, n) without modulo bias, by rejecting the values at the top of
+// the range which would make the low numbers more likely.
+func uniform(r io.Reader, n uint64) (uint64, error) {
+	limit := ^uint64(0) - (^uint64(0)%n+1)%n
+	var b [8]byte
+	for {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		if v := binary.BigEndian.Uint64(b[:]); v <= limit {
+			return v % n, nil
+		}
+	}
+}
+
+// NonceSize is the number of random bytes mixed into a commitment.
+const NonceSize = 32
+
+// Commitment is a hash of the order of some cards, e.g. a shoe, which can be published before they're
+// dealt without giving the order away. Once play is over the cards and the nonce are revealed, and anyone
+// can check with Verify that the cards weren't changed after the commitment was made.
+type Commitment [sha256.Size]byte
+
+// Commit returns a commitment to the order of the cards, and the random nonce which has to be revealed
+// with them. The nonce stops anyone working out the order from the commitment by hashing every order
+// which is left as cards are dealt.
+func Commit(cards []Card) (Commitment, []byte, error) {
+	nonce := make([]byte, NonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return Commitment{}, nil, fmt.Errorf("deck: reading randomness: %w", err)
+	}
+	return commitment(cards, nonce), nonce, nil
+}
+
+// Verify returns true if the commitment was made to the cards, in the same order, with the nonce.
+func Verify(c Commitment, cards []Card, nonce []byte) bool {
+	want := commitment(cards, nonce)
+	return subtle.ConstantTimeCompare(c[:], want[:]) == 1
+}
+
+// commitment hashes the nonce followed by the cards in the form FormatHand writes them, so the revealed
+// cards can be published and checked as text.
+func commitment(cards []Card, nonce []byte) Commitment {
+	var b bytes.Buffer
+	b.Write(nonce)
+	b.WriteString(FormatHand(cards))
+	return sha256.Sum256(b.Bytes())
+}
+
+func (c Commitment) String() string {
+	return hex.EncodeToString(c[:])
+}
+
+// ParseCommitment reads a commitment written by Commitment.String.
+func ParseCommitment(s string) (Commitment, error) {
+	var c Commitment
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(c) {
+		return c, fmt.Errorf("deck: invalid commitment %q", s)
+	}
+	copy(c[:], b)
+	return c, nil
+}
@@ -0,0 +1,100 @@
+package deck
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSecureShuffle(t *testing.T) {
+	seen := make(map[int]bool)
+	for _, p := range index(New(SecureShuffle)) {
+		seen[p] = true
+	}
+	if len(seen) != 52 {
+		t.Errorf("Expected all 52 cards after shuffling, received %d distinct cards.", len(seen))
+	}
+	// see TestShuffleBias
+	if chi := topCardChiSquare(SecureShuffle, 10000); chi > 90 {
+		t.Errorf("Expected an unbiased top card position, chi-square %.1f.", chi)
+	}
+	if _, err := secureShuffle(bytes.NewReader(nil), New()); err == nil {
+		t.Error("Expected an error when randomness can't be read, received nil.")
+	}
+}
+
+func TestUniform(t *testing.T) {
+	// 2^64 leaves a remainder of 1 when divided by 3, so the largest value is rejected
+	r := bytes.NewReader([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe,
+	})
+	n, err := uniform(r, 3)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if n != 2 || r.Len() != 0 {
+		t.Errorf("Expected the first value to be rejected and 2 returned, received %d with %d bytes left.", n, r.Len())
+	}
+}
+
+func TestCommit(t *testing.T) {
+	shoe := NewShoe(1, Deck(2), SecureShuffle)
+	c, nonce, err := Commit(shoe.Cards())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	for shoe.Remaining() > 0 {
+		shoe.Draw()
+	}
+	cards := shoe.Cards()
+	if !Verify(c, cards, nonce) {
+		t.Error("Expected the revealed shoe to match the commitment.")
+	}
+	swapped := append([]Card(nil), cards...)
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+	if cards[0] != cards[1] && Verify(c, swapped, nonce) {
+		t.Error("Expected a different order not to match the commitment.")
+	}
+	other := append([]byte(nil), nonce...)
+	other[0]++
+	if Verify(c, cards, other) {
+		t.Error("Expected a different nonce not to match the commitment.")
+	}
+	again, _, err := Commit(cards)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if again == c {
+		t.Error("Expected a new nonce to give a new commitment to the same cards.")
+	}
+}
+
+func TestParseCommitment(t *testing.T) {
+	c, _, err := Commit(New())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	parsed, err := ParseCommitment(c.String())
+	if err != nil || parsed != c {
+		t.Errorf("Expected %s to parse back, received %s, %v.", c, parsed, err)
+	}
+	for _, s := range []string{"", "zz", c.String()[2:]} {
+		if _, err := ParseCommitment(s); err == nil {
+			t.Errorf("Expected an error parsing %q, received nil.", s)
+		}
+	}
+}
+
+// failingReader always fails, like crypto/rand when the operating system has no randomness.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("no randomness")
+}
+
+func TestUniformError(t *testing.T) {
+	if _, err := uniform(failingReader{}, 52); err == nil {
+		t.Error("Expected an error when randomness can't be read, received nil.")
+	}
+}
@@ -0,0 +1,120 @@
+package deck
+
+import "errors"
+
+// ErrEmptyShoe is returned when drawing or burning more cards than remain in a shoe.
+var ErrEmptyShoe = errors.New("no cards left in the shoe")
+
+// Shoe is a stateful source of cards, like the shoe a dealer deals from. It is built with New and the
+// given options, and tracks the cards dealt, a discard pile, and a cut card placed at a configurable
+// penetration which signals when the shoe needs to be reshuffled.
+type Shoe struct {
+	opts        []func([]Card) []Card
+	penetration float64
+
+	cards   []Card
+	discard []Card
+	dealt   int
+	cut     int // number of cards which can be dealt before the cut card is reached
+}
+
+// NewShoe returns a shoe whose cards are built by New(opts...), which is only shuffled if one of the options
+// shuffles it, e.g. Shuffle or Seed. Penetration is the fraction
+// of the shoe (between 0 and 1) dealt before the cut card comes out; values outside that range deal the
+// whole shoe. The options are re-applied on every Shuffle, so a seeded shuffle option produces the
+// same sequence of shoes.
+func NewShoe(penetration float64, opts ...func([]Card) []Card) *Shoe {
+	if penetration <= 0 || penetration > 1 {
+		penetration = 1
+	}
+	s := &Shoe{
+		opts:        opts,
+		penetration: penetration,
+	}
+	s.Shuffle()
+	return s
+}
+
+// Shuffle rebuilds the shoe from its options, empties the discard pile and places the cut card.
+func (s *Shoe) Shuffle() {
+	s.cards = New(s.opts...)
+	s.discard = nil
+	s.dealt = 0
+	s.cut = int(float64(len(s.cards)) * s.penetration)
+}
+
+// Draw deals the next card from the shoe.
+func (s *Shoe) Draw() (Card, error) {
+	if s.Remaining() == 0 {
+		return Card{}, ErrEmptyShoe
+	}
+	card := s.cards[s.dealt]
+	s.dealt++
+	return card, nil
+}
+
+// Burn deals n cards straight onto the discard pile.
+func (s *Shoe) Burn(n int) error {
+	if n < 0 {
+		return errors.New("can't burn a negative number of cards")
+	}
+	if n > s.Remaining() {
+		return ErrEmptyShoe
+	}
+	s.discard = append(s.discard, s.cards[s.dealt:s.dealt+n]...)
+	s.dealt += n
+	return nil
+}
+
+// Discard places cards which have been played onto the discard pile.
+func (s *Shoe) Discard(cards ...Card) {
+	s.discard = append(s.discard, cards...)
+}
+
+// Discards returns a copy of the discard pile, oldest card first.
+func (s *Shoe) Discards() []Card {
+	ret := make([]Card, len(s.discard))
+	copy(ret, s.discard)
+	return ret
+}
+
+// Cards returns a copy of every card in the shoe in the order they're dealt, including the cards already
+// dealt. With Commit it can be used to commit to a shoe before it's dealt and reveal it afterwards.
+func (s *Shoe) Cards() []Card {
+	ret := make([]Card, len(s.cards))
+	copy(ret, s.cards)
+	return ret
+}
+
+// Remaining returns the number of cards which have not been dealt yet.
+func (s *Shoe) Remaining() int {
+	return len(s.cards) - s.dealt
+}
+
+// Size returns the total number of cards in the shoe.
+func (s *Shoe) Size() int {
+	return len(s.cards)
+}
+
+// Penetration returns the fraction of the shoe dealt before the cut card.
+func (s *Shoe) Penetration() float64 {
+	return s.penetration
+}
+
+// NeedsShuffle returns true once the cut card has been reached. Games typically finish the current
+// round and shuffle before the next one.
+func (s *Shoe) NeedsShuffle() bool {
+	return s.dealt >= s.cut
+}
+
+// Clone returns a deep copy of the shoe's cards. Both shoes share the same options, so a clone of a shoe
+// built with a seeded shuffle draws from the same source of randomness when reshuffled: shuffling either
+// shoe changes the order the other one gets on its next shuffle. Clone is meant for dealing out the current
+// shoe more than once, e.g. to replay a round, rather than for shuffling independently.
+func (s *Shoe) Clone() *Shoe {
+	ret := *s
+	ret.cards = make([]Card, len(s.cards))
+	copy(ret.cards, s.cards)
+	ret.discard = s.Discards()
+	return &ret
+}
@@ -0,0 +1,78 @@
+package deck
+
+import "testing"
+
+func TestShoeDraw(t *testing.T) {
+	shoe := NewShoe(1, Deck(2))
+	original := New(Deck(2))
+	for i := 0; i < 3; i++ {
+		card, err := shoe.Draw()
+		if err != nil {
+			t.Fatal("Unexpected error drawing a card:", err)
+		}
+		if card != original[i] {
+			t.Errorf("Expected card %d to be %s, received %s.", i, original[i], card)
+		}
+	}
+	if shoe.Remaining() != 13*4*2-3 {
+		t.Errorf("Expected %d cards remaining, received %d.", 13*4*2-3, shoe.Remaining())
+	}
+}
+
+func TestShoeEmpty(t *testing.T) {
+	shoe := NewShoe(1, Filter(func(c Card) bool { return c.Suit != Heart }))
+	if err := shoe.Burn(13); err != nil {
+		t.Fatal("Unexpected error burning cards:", err)
+	}
+	if _, err := shoe.Draw(); err != ErrEmptyShoe {
+		t.Errorf("Expected ErrEmptyShoe, received %v.", err)
+	}
+	if err := shoe.Burn(1); err != ErrEmptyShoe {
+		t.Errorf("Expected ErrEmptyShoe, received %v.", err)
+	}
+	if err := NewShoe(1).Burn(-1); err == nil {
+		t.Error("Expected an error burning a negative number of cards, received nil.")
+	}
+}
+
+func TestShoeNeedsShuffle(t *testing.T) {
+	shoe := NewShoe(0.75)
+	if err := shoe.Burn(38); err != nil {
+		t.Fatal("Unexpected error burning cards:", err)
+	}
+	if shoe.NeedsShuffle() {
+		t.Error("Expected shoe to not need a shuffle before the cut card.")
+	}
+	shoe.Draw()
+	if !shoe.NeedsShuffle() {
+		t.Error("Expected shoe to need a shuffle once the cut card is reached.")
+	}
+	shoe.Shuffle()
+	if shoe.NeedsShuffle() || shoe.Remaining() != 52 || len(shoe.Discards()) != 0 {
+		t.Error("Expected shuffle to restore a full shoe with an empty discard pile.")
+	}
+}
+
+func TestShoeDiscard(t *testing.T) {
+	shoe := NewShoe(1)
+	shoe.Burn(1)
+	card, _ := shoe.Draw()
+	shoe.Discard(card)
+	discards := shoe.Discards()
+	if len(discards) != 2 || discards[1] != card {
+		t.Errorf("Expected burned card and %s in the discard pile, received %v.", card, discards)
+	}
+}
+
+func TestShoeSeeded(t *testing.T) {
+	a, b := NewShoe(0.5, Deck(2), Seed(3)), NewShoe(0.5, Deck(2), Seed(3))
+	a.Shuffle()
+	b.Shuffle()
+	for a.Remaining() > 0 {
+		x, _ := a.Draw()
+		y, _ := b.Draw()
+		if x != y {
+			t.Fatalf("Expected seeded shoes to deal the same cards, received %s and %s.", x, y)
+		}
+	}
+}
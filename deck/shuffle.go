@@ -0,0 +1,123 @@
+package deck
+
+import "math/rand"
+
+// The options in this file model the ways people actually shuffle cards rather than the perfectly uniform
+// permutation produced by Shuffle. Each takes the source of randomness to use; if src is nil the same
+// source as Shuffle is used.
+
+func newRand(src rand.Source) *rand.Rand {
+	if src == nil {
+		return shuffleRand
+	}
+	return rand.New(src)
+}
+
+// Riffle returns an option which performs n riffle shuffles following the Gilbert–Shannon–Reeds model:
+// the deck is cut binomially into two packets which are then interleaved, dropping a card from each packet
+// with probability proportional to its size. Around 7 riffles are needed to mix a 52 card deck.
+func Riffle(n int, src rand.Source) func([]Card) []Card {
+	r := newRand(src)
+	return func(cards []Card) []Card {
+		for i := 0; i < n; i++ {
+			cards = riffle(r, cards)
+		}
+		return cards
+	}
+}
+
+func riffle(r *rand.Rand, cards []Card) []Card {
+	k := binomialCut(r, len(cards))
+	left, right := cards[:k], cards[k:]
+	ret := make([]Card, 0, len(cards))
+	for len(left) > 0 || len(right) > 0 {
+		if r.Intn(len(left)+len(right)) < len(left) {
+			ret = append(ret, left[0])
+			left = left[1:]
+		} else {
+			ret = append(ret, right[0])
+			right = right[1:]
+		}
+	}
+	return ret
+}
+
+// binomialCut returns the number of cards in the top packet of a cut, distributed Binomial(n, 1/2).
+func binomialCut(r *rand.Rand, n int) int {
+	k := 0
+	for i := 0; i < n; i++ {
+		k += r.Intn(2)
+	}
+	return k
+}
+
+// Overhand returns an option which performs n overhand shuffles: small packets are repeatedly slid off
+// the top of the deck onto a new pile, reversing the order of the packets but not the cards within them.
+func Overhand(n int, src rand.Source) func([]Card) []Card {
+	r := newRand(src)
+	return func(cards []Card) []Card {
+		for i := 0; i < n; i++ {
+			cards = packets(r, cards, 1, 8)
+		}
+		return cards
+	}
+}
+
+// Strip returns an option which performs n strip shuffles. A strip is an overhand shuffle with larger
+// packets, as dealers do between riffles: the deck is stripped into roughly 4 to 6 packets.
+func Strip(n int, src rand.Source) func([]Card) []Card {
+	r := newRand(src)
+	return func(cards []Card) []Card {
+		for i := 0; i < n; i++ {
+			cards = packets(r, cards, len(cards)/8+1, len(cards)/4+1)
+		}
+		return cards
+	}
+}
+
+// packets takes packets of between min and max cards off the top of cards and stacks them on a new pile.
+func packets(r *rand.Rand, cards []Card, min, max int) []Card {
+	ret := make([]Card, len(cards))
+	end := len(ret)
+	for len(cards) > 0 {
+		size := min + r.Intn(max-min+1)
+		if size > len(cards) {
+			size = len(cards)
+		}
+		copy(ret[end-size:end], cards[:size])
+		end -= size
+		cards = cards[size:]
+	}
+	return ret
+}
+
+// Cut returns an option which cuts the deck once near the middle, moving the top packet to the bottom.
+func Cut(src rand.Source) func([]Card) []Card {
+	r := newRand(src)
+	return func(cards []Card) []Card {
+		k := binomialCut(r, len(cards))
+		ret := make([]Card, 0, len(cards))
+		ret = append(ret, cards[k:]...)
+		return append(ret, cards[:k]...)
+	}
+}
+
+// Compose returns an option which applies each of the given options in order, e.g. to describe the
+// exact shuffle procedure a casino uses.
+func Compose(opts ...func([]Card) []Card) func([]Card) []Card {
+	return func(cards []Card) []Card {
+		for _, opt := range opts {
+			cards = opt(cards)
+		}
+		return cards
+	}
+}
+
+// Human returns an option which shuffles the way a dealer typically does by hand:
+// riffle, riffle, strip, riffle, cut.
+func Human(src rand.Source) func([]Card) []Card {
+	if src == nil {
+		src = shuffleRand
+	}
+	return Compose(Riffle(2, src), Strip(1, src), Riffle(1, src), Cut(src))
+}
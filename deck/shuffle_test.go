@@ -0,0 +1,136 @@
+package deck
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// index maps each card of a single deck to its position in the deck returned by New.
+func index(cards []Card) []int {
+	pos := make(map[Card]int)
+	for i, c := range New() {
+		pos[c] = i
+	}
+	ret := make([]int, len(cards))
+	for i, c := range cards {
+		ret[i] = pos[c]
+	}
+	return ret
+}
+
+// risingSequences counts the maximal runs of consecutive original positions, which is the classic measure
+// of how well a riffle shuffled deck is mixed: k riffles produce at most 2^k rising sequences.
+func risingSequences(perm []int) int {
+	where := make([]int, len(perm))
+	for i, p := range perm {
+		where[p] = i
+	}
+	count := 1
+	for v := 1; v < len(where); v++ {
+		if where[v] < where[v-1] {
+			count++
+		}
+	}
+	return count
+}
+
+// topCardChiSquare shuffles a fresh deck trials times and returns the chi-square statistic of the position the
+// original top card ends up in, against a uniform distribution over the 52 positions.
+func topCardChiSquare(opt func([]Card) []Card, trials int) float64 {
+	counts := make([]int, 52)
+	for i := 0; i < trials; i++ {
+		for j, p := range index(opt(New())) {
+			if p == 0 {
+				counts[j]++
+				break
+			}
+		}
+	}
+	expected := float64(trials) / 52
+	chi := 0.0
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi += d * d / expected
+	}
+	return chi
+}
+
+func TestShufflesArePermutations(t *testing.T) {
+	src := rand.NewSource(1)
+	opts := map[string]func([]Card) []Card{
+		"Riffle":   Riffle(3, src),
+		"Overhand": Overhand(3, src),
+		"Strip":    Strip(3, src),
+		"Cut":      Cut(src),
+		"Human":    Human(src),
+	}
+	for name, opt := range opts {
+		seen := make(map[int]bool)
+		for _, p := range index(opt(New())) {
+			seen[p] = true
+		}
+		if len(seen) != 52 {
+			t.Errorf("%s: expected all 52 cards after shuffling, received %d distinct cards.", name, len(seen))
+		}
+	}
+}
+
+func TestRiffleRisingSequences(t *testing.T) {
+	src := rand.NewSource(2)
+	for n := 1; n <= 4; n++ {
+		opt := Riffle(n, src)
+		for i := 0; i < 100; i++ {
+			if got := risingSequences(index(opt(New()))); got > 1<<uint(n) {
+				t.Fatalf("Expected at most %d rising sequences after %d riffles, received %d.", 1<<uint(n), n, got)
+			}
+		}
+	}
+}
+
+func TestCutKeepsCyclicOrder(t *testing.T) {
+	perm := index(New(Cut(rand.NewSource(3))))
+	for i := range perm {
+		if perm[(i+1)%len(perm)] != (perm[i]+1)%len(perm) {
+			t.Fatalf("Expected a cut to keep the cyclic order of the deck, received %v.", perm)
+		}
+	}
+}
+
+func TestShuffleBias(t *testing.T) {
+	const trials = 10000
+	// 51 degrees of freedom: a uniform shuffle exceeds 90 with probability well under 0.1%
+	const critical = 90.0
+	if chi := topCardChiSquare(ShuffleWith(rand.NewSource(4)), trials); chi > critical {
+		t.Errorf("Shuffle: expected an unbiased top card position, chi-square %.1f.", chi)
+	}
+	if chi := topCardChiSquare(Riffle(12, rand.NewSource(5)), trials); chi > critical {
+		t.Errorf("Riffle(12): expected an unbiased top card position, chi-square %.1f.", chi)
+	}
+	// too few riffles leave the top card near the top; the bias shrinks quickly with each riffle
+	// but is still measurable after the customary 7
+	prev := 0.0
+	for i, n := range []int{7, 4, 1} {
+		chi := topCardChiSquare(Riffle(n, rand.NewSource(int64(6+i))), trials)
+		if chi < critical || chi < 10*prev {
+			t.Errorf("Riffle(%d): expected a strongly biased top card position, chi-square %.1f.", n, chi)
+		}
+		prev = chi
+	}
+	if chi := topCardChiSquare(Overhand(1, rand.NewSource(9)), trials); chi < 10*critical {
+		t.Errorf("Overhand(1): expected a strongly biased top card position, chi-square %.1f.", chi)
+	}
+}
+
+func TestOverhandKeepsPackets(t *testing.T) {
+	// an overhand shuffle keeps most neighbouring cards together, a uniform shuffle keeps about 1 pair in 52
+	perm := index(New(Overhand(1, rand.NewSource(8))))
+	kept := 0
+	for i := 1; i < len(perm); i++ {
+		if perm[i] == perm[i-1]+1 {
+			kept++
+		}
+	}
+	if kept < len(perm)/2 {
+		t.Errorf("Expected an overhand shuffle to keep most neighbouring cards together, kept %d.", kept)
+	}
+}
@@ -13,11 +13,12 @@ func _() {
 	_ = x[Club-2]
 	_ = x[Heart-3]
 	_ = x[Joker-4]
+	_ = x[Trumps-5]
 }
 
-const _Suit_name = "SpadeDiamondClubHeartJoker"
+const _Suit_name = "SpadeDiamondClubHeartJokerTrumps"
 
-var _Suit_index = [...]uint8{0, 5, 12, 16, 21, 26}
+var _Suit_index = [...]uint8{0, 5, 12, 16, 21, 26, 32}
 
 func (i Suit) String() string {
 	if i >= Suit(len(_Suit_index)-1) {
@@ -42,11 +43,12 @@ func _() {
 	_ = x[Jack-11]
 	_ = x[Queen-12]
 	_ = x[King-13]
+	_ = x[Knight-14]
 }
 
-const _Rank_name = "AceTwoThreeFourFiveSixSevenEightNineTenJackQueenKing"
+const _Rank_name = "AceTwoThreeFourFiveSixSevenEightNineTenJackQueenKingKnight"
 
-var _Rank_index = [...]uint8{0, 3, 6, 11, 15, 19, 22, 27, 32, 36, 39, 43, 48, 52}
+var _Rank_index = [...]uint8{0, 3, 6, 11, 15, 19, 22, 27, 32, 36, 39, 43, 48, 52, 58}
 
 func (i Rank) String() string {
 	i -= 1
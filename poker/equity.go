@@ -0,0 +1,92 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Equity estimates each player's share of the pot by dealing out the rest of the board (up to 5 cards)
+// trials times from the cards not already in a hand or on the board. Ties split the pot evenly. It returns an
+// error if there are no hands, trials isn't positive, the board has more than 5 cards or a card is dealt twice.
+func Equity(hands [][]deck.Card, board []deck.Card, trials int, src rand.Source) ([]float64, error) {
+	if len(hands) == 0 {
+		return nil, errors.New("poker: no hands")
+	}
+	if trials <= 0 {
+		return nil, fmt.Errorf("poker: need a positive number of trials, got %d", trials)
+	}
+	if len(board) > 5 {
+		return nil, fmt.Errorf("poker: the board has %d cards, more than 5", len(board))
+	}
+	r := rand.New(src)
+	used := make(map[deck.Card]bool)
+	use := func(cards []deck.Card) error {
+		for _, c := range cards {
+			if used[c] {
+				return fmt.Errorf("poker: %s is dealt more than once", c)
+			}
+			used[c] = true
+		}
+		return nil
+	}
+	for _, h := range hands {
+		if err := use(h); err != nil {
+			return nil, err
+		}
+	}
+	if err := use(board); err != nil {
+		return nil, err
+	}
+	var stub []deck.Card
+	for _, c := range standard {
+		if !used[c] {
+			stub = append(stub, c)
+		}
+	}
+
+	missing := 5 - len(board)
+	if missing > len(stub) {
+		return nil, fmt.Errorf("poker: %d cards are left to deal the board, need %d", len(stub), missing)
+	}
+	full := make([]deck.Card, 5)
+	copy(full, board)
+	cards := make([][]deck.Card, len(hands))
+	for i, h := range hands {
+		cards[i] = make([]deck.Card, len(h)+5)
+		copy(cards[i], h)
+	}
+	shares := make([]float64, len(hands))
+	values := make([]Value, len(hands))
+	for t := 0; t < trials; t++ {
+		// partial Fisher–Yates: only the first missing cards of the stub need to be random
+		for i := 0; i < missing; i++ {
+			j := i + r.Intn(len(stub)-i)
+			stub[i], stub[j] = stub[j], stub[i]
+		}
+		copy(full[len(board):], stub[:missing])
+		var best Value
+		winners := 0
+		for i, h := range hands {
+			copy(cards[i][len(h):], full)
+			values[i] = Evaluate(cards[i]...)
+			switch {
+			case values[i] > best:
+				best, winners = values[i], 1
+			case values[i] == best:
+				winners++
+			}
+		}
+		for i := range hands {
+			if values[i] == best {
+				shares[i] += 1 / float64(winners)
+			}
+		}
+	}
+	for i := range shares {
+		shares[i] /= float64(trials)
+	}
+	return shares, nil
+}
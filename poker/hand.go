@@ -0,0 +1,227 @@
+// Package poker ranks poker hands made of cards from the deck package.
+package poker
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Category is the kind of a poker hand, from HighCard up to FiveOfAKind.
+type Category uint8
+
+const (
+	HighCard Category = iota
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+	FiveOfAKind // only possible with wild cards
+)
+
+var categoryNames = [...]string{"High Card", "One Pair", "Two Pair", "Three of a Kind", "Straight", "Flush", "Full House", "Four of a Kind", "Straight Flush", "Five of a Kind"}
+
+func (c Category) String() string {
+	if int(c) >= len(categoryNames) {
+		return fmt.Sprintf("Category(%d)", c)
+	}
+	return categoryNames[c]
+}
+
+// Value is the strength of the best five card hand that can be made from a set of cards. Values can be
+// compared directly: a greater value beats a lower one and equal values split the pot. A royal flush is
+// simply the highest StraightFlush.
+//
+// The category is stored in the high bits, followed by up to five ranks (4 bits each, 0 is a two and 12
+// is an ace) ordered by importance, e.g. the trips then the pair of a full house, or the pair then the kickers.
+type Value uint32
+
+const categoryShift = 20
+
+// Category returns the kind of hand.
+func (v Value) Category() Category {
+	return Category(v >> categoryShift)
+}
+
+func (v Value) String() string {
+	return v.Category().String()
+}
+
+// lookup tables indexed by a 13 bit mask of ranks (bit 0 is a two, bit 12 an ace)
+var (
+	straightHigh [1 << 13]uint8  // 1 + rank of the highest straight in the mask, 0 if there isn't one
+	topRanks     [1 << 13]uint32 // the highest 5 ranks in the mask, packed 4 bits each with the highest first
+)
+
+func init() {
+	for mask := 0; mask < len(topRanks); mask++ {
+		var packed uint32
+		n := 0
+		for r := 12; r >= 0 && n < 5; r-- {
+			if mask&(1<<uint(r)) != 0 {
+				packed |= uint32(r) << uint(4*(4-n))
+				n++
+			}
+		}
+		topRanks[mask] = packed
+
+		for high := 12; high >= 3; high-- {
+			var need int
+			if high == 3 { // the wheel, A-2-3-4-5, plays the ace low
+				need = 1<<12 | 0xf
+			} else {
+				need = 0x1f << uint(high-4)
+			}
+			if mask&need == need {
+				straightHigh[mask] = uint8(high + 1)
+				break
+			}
+		}
+	}
+}
+
+// top returns the highest n ranks of mask packed into the low nibbles of a Value.
+func top(mask uint16, n int) Value {
+	return Value(topRanks[mask] >> uint(4*(5-n)))
+}
+
+func rankIndex(r deck.Rank) uint {
+	if r == deck.Ace {
+		return 12
+	}
+	return uint(r) - 2
+}
+
+// Evaluate returns the value of the best five card hand that can be made from cards, usually 5 to 7 of
+// them. Jokers are wild and take whichever rank and suit makes the best hand.
+func Evaluate(cards ...deck.Card) Value {
+	var jokers int
+	for _, c := range cards {
+		if c.Suit == deck.Joker {
+			jokers++
+		}
+	}
+	if jokers == 0 {
+		return evaluate(cards)
+	}
+	hand := make([]deck.Card, 0, len(cards))
+	for _, c := range cards {
+		if c.Suit != deck.Joker {
+			hand = append(hand, c)
+		}
+	}
+	return wild(hand, jokers)
+}
+
+// wild tries every card in place of each remaining joker and returns the best value found.
+func wild(hand []deck.Card, jokers int) Value {
+	if jokers == 0 {
+		return evaluate(hand)
+	}
+	var best Value
+	for _, c := range standard {
+		if v := wild(append(hand, c), jokers-1); v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+var standard = deck.New()
+
+func evaluate(cards []deck.Card) Value {
+	var (
+		counts [13]uint8
+		suits  [4]uint16
+		ranks  uint16
+	)
+	for _, c := range cards {
+		r := rankIndex(c.Rank)
+		counts[r]++
+		suits[c.Suit&3] |= 1 << r
+		ranks |= 1 << r
+	}
+
+	for _, mask := range suits {
+		if bits.OnesCount16(mask) >= 5 {
+			if high := straightHigh[mask]; high != 0 {
+				return Value(StraightFlush)<<categoryShift | Value(high-1)
+			}
+			flush := Value(Flush)<<categoryShift | top(mask, 5)
+			if v := groups(counts, ranks); v > flush {
+				return v
+			}
+			return flush
+		}
+	}
+	v := groups(counts, ranks)
+	if v.Category() < Straight {
+		if high := straightHigh[ranks]; high != 0 {
+			return Value(Straight)<<categoryShift | Value(high-1)
+		}
+	}
+	return v
+}
+
+// groups returns the value of the hand made from the ranks alone, i.e. anything other than a straight or flush.
+func groups(counts [13]uint8, ranks uint16) Value {
+	var (
+		quads, trips, pairs uint16
+		five                int = -1
+	)
+	for r := 12; r >= 0; r-- {
+		switch {
+		case counts[r] >= 5:
+			if five < 0 {
+				five = r
+			}
+		case counts[r] == 4:
+			quads |= 1 << uint(r)
+		case counts[r] == 3:
+			trips |= 1 << uint(r)
+		case counts[r] == 2:
+			pairs |= 1 << uint(r)
+		}
+	}
+	switch {
+	case five >= 0:
+		return Value(FiveOfAKind)<<categoryShift | Value(five)
+	case quads != 0:
+		q := top(quads, 1)
+		return Value(FourOfAKind)<<categoryShift | q<<4 | top(ranks&^(1<<q), 1)
+	case trips != 0 && (bits.OnesCount16(trips) > 1 || pairs != 0):
+		t := top(trips, 1)
+		p := top((trips|pairs)&^(1<<t), 1)
+		return Value(FullHouse)<<categoryShift | t<<4 | p
+	case trips != 0:
+		t := top(trips, 1)
+		return Value(ThreeOfAKind)<<categoryShift | t<<8 | top(ranks&^(1<<t), 2)
+	case bits.OnesCount16(pairs) >= 2:
+		p := top(pairs, 2)
+		high, low := p>>4, p&0xf
+		return Value(TwoPair)<<categoryShift | p<<4 | top(ranks&^(1<<high|1<<low), 1)
+	case pairs != 0:
+		p := top(pairs, 1)
+		return Value(OnePair)<<categoryShift | p<<12 | top(ranks&^(1<<p), 3)
+	default:
+		return Value(HighCard)<<categoryShift | top(ranks, 5)
+	}
+}
+
+// Compare returns 1 if hand a beats hand b, -1 if b beats a and 0 if they tie.
+func Compare(a, b []deck.Card) int {
+	va, vb := Evaluate(a...), Evaluate(b...)
+	switch {
+	case va > vb:
+		return 1
+	case va < vb:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,174 @@
+package poker
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+func hand(t testing.TB, s string) []deck.Card {
+	cards, err := deck.ParseHand(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+func ExampleEvaluate() {
+	cards, _ := deck.ParseHand("AS KS QS JS 10S 2D 2C")
+	fmt.Println(Evaluate(cards...))
+	cards, _ = deck.ParseHand("9H 9D 9C 4S 4D")
+	fmt.Println(Evaluate(cards...))
+
+	// Output:
+	// Straight Flush
+	// Full House
+}
+
+func TestEvaluateCategory(t *testing.T) {
+	tests := []struct {
+		cards string
+		want  Category
+	}{
+		{"2S 5D 9C JH KS", HighCard},
+		{"2S 2D 9C JH KS 3C 4D", OnePair},
+		{"2S 2D 9C 9H KS", TwoPair},
+		{"7S 7D 7C JH KS", ThreeOfAKind},
+		{"AS 2D 3C 4H 5S KD", Straight},
+		{"10S JD QC KH AS", Straight},
+		{"2H 5H 9H JH KH 3H", Flush},
+		{"7S 7D 7C KH KS 2D 2C", FullHouse},
+		{"7S 7D 7C 2H 2S 2D", FullHouse},
+		{"7S 7D 7C 7H KS KD KC", FourOfAKind},
+		{"AH 2H 3H 4H 5H 6D", StraightFlush},
+		{"5H 6H 7H 8H 9H 9S 9D", StraightFlush},
+		{"AS AD AC AH JK", FiveOfAKind},
+		{"AS KS QS JS JK", StraightFlush},
+		{"2S 2D JK JK1 9C", FourOfAKind},
+	}
+	for _, tt := range tests {
+		if got := Evaluate(hand(t, tt.cards)...).Category(); got != tt.want {
+			t.Errorf("Evaluate(%s): want %s, got %s", tt.cards, tt.want, got)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"AS KD 9C 7H 5S", "AS KD 9C 7H 4S", 1},       // last kicker
+		{"2S 2D AC KH QS", "3S 3D 4C 5H 7S", -1},      // pair rank beats kickers
+		{"KS KD 2C 2H 9S", "KS KD 3C 3H 4S", -1},      // second pair
+		{"AS 2D 3C 4H 5S", "2S 3D 4C 5H 6S", -1},      // the wheel is the lowest straight
+		{"QS QD QC 2H 2S", "JS JD JC AH AS", 1},       // trips decide a full house
+		{"AS KS 9S 7S 5S", "AD KD 9D 7D 5D", 0},       // suits never break ties
+		{"10H JH QH KH AH", "9S 9D 9C 9H AS", 1},      // royal flush
+		{"8S 8D 8C 8H 2S 3C 3D", "8S 8D 8C 8H 3S", 0}, // best five cards only
+	}
+	for _, tt := range tests {
+		if got := Compare(hand(t, tt.a), hand(t, tt.b)); got != tt.want {
+			t.Errorf("Compare(%s, %s): want %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestEvaluateDistribution(t *testing.T) {
+	// there are exactly 2,598,960 five card hands with well known counts for each category
+	want := map[Category]int{
+		HighCard:      1302540,
+		OnePair:       1098240,
+		TwoPair:       123552,
+		ThreeOfAKind:  54912,
+		Straight:      10200,
+		Flush:         5108,
+		FullHouse:     3744,
+		FourOfAKind:   624,
+		StraightFlush: 40,
+	}
+	if testing.Short() {
+		t.Skip("skipping enumeration of every five card hand")
+	}
+	cards := deck.New()
+	got := make(map[Category]int)
+	hand := make([]deck.Card, 5)
+	for a := 0; a < 52; a++ {
+		for b := a + 1; b < 52; b++ {
+			for c := b + 1; c < 52; c++ {
+				for d := c + 1; d < 52; d++ {
+					for e := d + 1; e < 52; e++ {
+						hand[0], hand[1], hand[2], hand[3], hand[4] = cards[a], cards[b], cards[c], cards[d], cards[e]
+						got[Evaluate(hand...).Category()]++
+					}
+				}
+			}
+		}
+	}
+	for cat, n := range want {
+		if got[cat] != n {
+			t.Errorf("%s: want %d hands, got %d", cat, n, got[cat])
+		}
+	}
+}
+
+func TestEquity(t *testing.T) {
+	// pocket aces are roughly an 81% favourite over pocket kings before the flop
+	hands := [][]deck.Card{hand(t, "AS AH"), hand(t, "KS KH")}
+	eq, err := Equity(hands, nil, 20000, rand.NewSource(1))
+	if err != nil {
+		t.Fatal("Equity: unexpected error:", err)
+	}
+	if math.Abs(eq[0]-0.82) > 0.02 || math.Abs(eq[0]+eq[1]-1) > 1e-9 {
+		t.Errorf("Equity(AA vs KK): want about 0.82, got %v", eq)
+	}
+	// the river is already dealt, so the result is exact
+	eq, err = Equity(hands, hand(t, "2C 7D 9H JC KD"), 10, rand.NewSource(1))
+	if err != nil {
+		t.Fatal("Equity: unexpected error:", err)
+	}
+	if eq[1] != 1 {
+		t.Errorf("Equity(AA vs set of kings): want 1 for kings, got %v", eq)
+	}
+}
+
+func TestEquityInvalid(t *testing.T) {
+	aces, kings := hand(t, "AS AH"), hand(t, "KS KH")
+	tests := []struct {
+		name   string
+		hands  [][]deck.Card
+		board  string
+		trials int
+	}{
+		{"no hands", nil, "", 10},
+		{"no trials", [][]deck.Card{aces, kings}, "", 0},
+		{"six card board", [][]deck.Card{aces, kings}, "2C 7D 9H JC KD 3S", 10},
+		{"card in two hands", [][]deck.Card{aces, hand(t, "AS KH")}, "", 10},
+		{"card in a hand and on the board", [][]deck.Card{aces, kings}, "2C 7D KH", 10},
+	}
+	for _, tt := range tests {
+		if _, err := Equity(tt.hands, hand(t, tt.board), tt.trials, rand.NewSource(1)); err == nil {
+			t.Errorf("%s: want an error, got nil", tt.name)
+		}
+	}
+}
+
+func BenchmarkEvaluate7(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	cards := deck.New()
+	hands := make([][]deck.Card, 1024)
+	for i := range hands {
+		perm := r.Perm(52)
+		hands[i] = make([]deck.Card, 7)
+		for j := range hands[i] {
+			hands[i][j] = cards[perm[j]]
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Evaluate(hands[i%len(hands)]...)
+	}
+}
@@ -0,0 +1,284 @@
+package solitaire
+
+import (
+	"fmt"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// FreeCell is a game of FreeCell: the whole deck is dealt face up to eight tableau piles, and four free
+// cells each hold a single card. Tableau piles are built down in alternating colours and any card can
+// fill an empty pile. Runs are moved as a whole, as long as there are enough free cells and empty piles
+// to move them one card at a time.
+type FreeCell struct {
+	tableau     [8][]deck.Card
+	cells       [4]deck.Card // a card with no rank is an empty cell
+	foundations [4]int
+
+	history []Move
+}
+
+// NewFreeCell deals a game from the cards, which must be a full 52 card deck, e.g. deck.New(deck.Seed(1)).
+// The cards are dealt in rows, so the first four piles get seven cards and the rest get six.
+func NewFreeCell(cards []deck.Card) (*FreeCell, error) {
+	if len(cards) != 52 {
+		return nil, fmt.Errorf("solitaire: need 52 cards, got %d", len(cards))
+	}
+	f := &FreeCell{}
+	for i, c := range cards {
+		f.tableau[i%8] = append(f.tableau[i%8], c)
+	}
+	return f, nil
+}
+
+// free returns the number of empty cells and empty tableau piles.
+func (f *FreeCell) free() (cells, piles int) {
+	for _, c := range f.cells {
+		if c.Rank == 0 {
+			cells++
+		}
+	}
+	for _, pile := range f.tableau {
+		if len(pile) == 0 {
+			piles++
+		}
+	}
+	return cells, piles
+}
+
+// run returns the number of cards at the top of the pile which are built down in alternating colours.
+func run(pile []deck.Card) int {
+	if len(pile) == 0 {
+		return 0
+	}
+	n := 1
+	for i := len(pile) - 1; i > 0 && stacks(pile[i], pile[i-1]); i-- {
+		n++
+	}
+	return n
+}
+
+func (f *FreeCell) Moves() []Move {
+	var moves []Move
+	for i, c := range f.cells {
+		if c.Rank != 0 && toFoundation(c, &f.foundations) {
+			moves = append(moves, Move{From: Pile{Cell, i}, To: Pile{Foundation, int(c.Suit)}, Count: 1})
+		}
+	}
+	for i, pile := range f.tableau {
+		if c, ok := top(pile); ok && toFoundation(c, &f.foundations) {
+			moves = append(moves, Move{From: Pile{Tableau, i}, To: Pile{Foundation, int(c.Suit)}, Count: 1})
+		}
+	}
+	cells, piles := f.free()
+	for i, pile := range f.tableau {
+		length := run(pile)
+		for j, dest := range f.tableau {
+			if i == j {
+				continue
+			}
+			// a run can be moved through the free cells, and through the empty piles other than the one
+			// it's moving to
+			limit := cells + 1
+			empty := piles
+			if len(dest) == 0 {
+				empty--
+			}
+			limit <<= uint(empty)
+			for n := 1; n <= length && n <= limit; n++ {
+				c := pile[len(pile)-n]
+				if t, ok := top(dest); !ok || stacks(c, t) {
+					moves = append(moves, Move{From: Pile{Tableau, i}, To: Pile{Tableau, j}, Count: n})
+				}
+			}
+		}
+	}
+	for i, c := range f.cells {
+		if c.Rank == 0 {
+			continue
+		}
+		for j, dest := range f.tableau {
+			if t, ok := top(dest); !ok || stacks(c, t) {
+				moves = append(moves, Move{From: Pile{Cell, i}, To: Pile{Tableau, j}, Count: 1})
+			}
+		}
+	}
+	for i, pile := range f.tableau {
+		if len(pile) == 0 {
+			continue
+		}
+		for j, c := range f.cells {
+			if c.Rank == 0 {
+				moves = append(moves, Move{From: Pile{Tableau, i}, To: Pile{Cell, j}, Count: 1})
+			}
+		}
+	}
+	return moves
+}
+
+func (f *FreeCell) Apply(m Move) error {
+	if !contains(f.Moves(), m) {
+		return fmt.Errorf("solitaire: illegal move %s", m)
+	}
+	f.put(m.To, f.take(m.From, m.Count))
+	f.history = append(f.history, m)
+	return nil
+}
+
+// take removes the top count cards of a pile and returns them.
+func (f *FreeCell) take(p Pile, count int) []deck.Card {
+	switch p.Kind {
+	case Foundation:
+		c := deck.Card{Suit: deck.Suit(p.Index), Rank: deck.Rank(f.foundations[p.Index])}
+		f.foundations[p.Index]--
+		return []deck.Card{c}
+	case Cell:
+		c := f.cells[p.Index]
+		f.cells[p.Index] = deck.Card{}
+		return []deck.Card{c}
+	}
+	pile := f.tableau[p.Index]
+	cards := append([]deck.Card(nil), pile[len(pile)-count:]...)
+	f.tableau[p.Index] = pile[:len(pile)-count]
+	return cards
+}
+
+// put places cards on top of a pile.
+func (f *FreeCell) put(p Pile, cards []deck.Card) {
+	switch p.Kind {
+	case Foundation:
+		f.foundations[p.Index] += len(cards)
+	case Cell:
+		f.cells[p.Index] = cards[0]
+	default:
+		f.tableau[p.Index] = append(f.tableau[p.Index], cards...)
+	}
+}
+
+func (f *FreeCell) Undo() bool {
+	if len(f.history) == 0 {
+		return false
+	}
+	m := f.history[len(f.history)-1]
+	f.history = f.history[:len(f.history)-1]
+	f.put(m.From, f.take(m.To, m.Count))
+	return true
+}
+
+func (f *FreeCell) Won() bool {
+	return f.foundations == [4]int{13, 13, 13, 13}
+}
+
+func (f *FreeCell) Hash() uint64 {
+	var h hasher
+	for _, rank := range f.foundations {
+		h.b = append(h.b, byte(rank))
+	}
+	cells := make([][]byte, 0, len(f.cells))
+	for _, c := range f.cells {
+		var p hasher
+		if c.Rank != 0 {
+			p.cards([]deck.Card{c})
+		}
+		cells = append(cells, p.b)
+	}
+	h.piles(cells)
+	piles := make([][]byte, len(f.tableau))
+	for i, pile := range f.tableau {
+		var p hasher
+		p.cards(pile)
+		piles[i] = p.b
+	}
+	h.piles(piles)
+	return h.sum()
+}
+
+// String draws the game.
+func (f *FreeCell) String() string {
+	s := "cells"
+	for _, c := range f.cells {
+		if c.Rank == 0 {
+			s += " --"
+		} else {
+			s += " " + deck.FormatHand([]deck.Card{c})
+		}
+	}
+	s += fmt.Sprintf("  foundations %v\n", f.foundations)
+	for i, pile := range f.tableau {
+		s += fmt.Sprintf("T%d: %s\n", i, deck.FormatHand(pile))
+	}
+	return s
+}
+
+// candidates returns the moves worth searching: a safe move to the foundations on its own if there is one,
+// and otherwise every move except those which are the same as another because they go to a different
+// empty cell or empty pile, which move a whole pile to an empty one, or which split a run between two
+// piles, since the rest of the run could just be moved back.
+func (f *FreeCell) candidates() []Move {
+	var moves []Move
+	emptyCell, emptyPile := -1, -1
+	for i, c := range f.cells {
+		if c.Rank == 0 {
+			emptyCell = i
+			break
+		}
+	}
+	for i, pile := range f.tableau {
+		if len(pile) == 0 {
+			emptyPile = i
+			break
+		}
+	}
+	for _, m := range f.Moves() {
+		switch {
+		case m.To.Kind == Foundation:
+			c, _ := top(f.tableau[m.From.Index])
+			if m.From.Kind == Cell {
+				c = f.cells[m.From.Index]
+			}
+			if safe(c, &f.foundations) {
+				return []Move{m}
+			}
+		case m.To.Kind == Cell && m.To.Index != emptyCell:
+			continue
+		case m.To.Kind == Tableau && len(f.tableau[m.To.Index]) == 0:
+			if m.To.Index != emptyPile || m.From.Kind == Tableau && m.Count == len(f.tableau[m.From.Index]) {
+				continue
+			}
+		case m.From.Kind == Tableau && m.Count < run(f.tableau[m.From.Index]):
+			continue
+		}
+		moves = append(moves, m)
+	}
+	return moves
+}
+
+// score estimates how far the game is from being won: the cards still to go to the foundations, plus the
+// cards in the cells and the cards on top of a lower card in the tableau, which have to be moved out of
+// the way first.
+func (f *FreeCell) score() int {
+	n := 52 - f.foundations[0] - f.foundations[1] - f.foundations[2] - f.foundations[3]
+	for _, c := range f.cells {
+		if c.Rank != 0 {
+			n++
+		}
+	}
+	for _, pile := range f.tableau {
+		n += blocking(pile)
+	}
+	return n
+}
+
+// blocking returns the number of cards in the pile which are on top of a lower card.
+func blocking(pile []deck.Card) int {
+	n := 0
+	for i, c := range pile {
+		for _, under := range pile[:i] {
+			if under.Rank < c.Rank {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
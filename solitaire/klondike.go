@@ -0,0 +1,346 @@
+package solitaire
+
+import (
+	"fmt"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// KlondikeOptions are the rules of a game of Klondike.
+type KlondikeOptions struct {
+	Draw   int // the number of cards turned from the stock at a time; defaults to 1
+	Passes int // the number of times the stock can be dealt through; 0 means there is no limit
+}
+
+// Klondike is a game of Klondike: seven tableau piles of one to seven cards, each with its top card face
+// up, and the rest of the deck in the stock. Tableau piles are built down in alternating colours, only
+// kings can fill an empty pile, and cards can be moved back from the foundations.
+type Klondike struct {
+	draw, passes int
+
+	stock       []deck.Card // the top card is the last one
+	waste       []deck.Card
+	foundations [4]int // the top rank on each suit's foundation
+	tableau     [7][]deck.Card
+	faceDown    [7]int // the number of face down cards at the bottom of each tableau pile
+	pass        int    // the number of times the waste has been turned back over
+
+	history []klondikeUndo
+}
+
+type klondikeUndo struct {
+	move    Move
+	flipped bool // the move turned over a face down tableau card
+	drawn   int  // the number of cards turned from the stock
+}
+
+// NewKlondike deals a game from the cards, which must be a full 52 card deck, e.g. deck.New(deck.Seed(1)).
+// The first card dealt is the first of cards.
+func NewKlondike(cards []deck.Card, opts KlondikeOptions) (*Klondike, error) {
+	if len(cards) != 52 {
+		return nil, fmt.Errorf("solitaire: need 52 cards, got %d", len(cards))
+	}
+	if opts.Draw <= 0 {
+		opts.Draw = 1
+	}
+	k := &Klondike{draw: opts.Draw, passes: opts.Passes}
+	n := 0
+	for row := 0; row < 7; row++ {
+		for pile := row; pile < 7; pile++ {
+			k.tableau[pile] = append(k.tableau[pile], cards[n])
+			n++
+		}
+	}
+	for i := range k.faceDown {
+		k.faceDown[i] = i
+	}
+	// the rest of the deck is the stock, with its first card on top
+	for i := 51; i >= n; i-- {
+		k.stock = append(k.stock, cards[i])
+	}
+	return k, nil
+}
+
+func top(cards []deck.Card) (deck.Card, bool) {
+	if len(cards) == 0 {
+		return deck.Card{}, false
+	}
+	return cards[len(cards)-1], true
+}
+
+// toFoundation returns true if the card can go on its foundation.
+func toFoundation(c deck.Card, foundations *[4]int) bool {
+	return int(c.Rank) == foundations[c.Suit]+1
+}
+
+// toTableau returns true if the card can go on the tableau pile.
+func (k *Klondike) toTableau(c deck.Card, pile int) bool {
+	t, ok := top(k.tableau[pile])
+	if !ok {
+		return c.Rank == deck.King
+	}
+	return stacks(c, t)
+}
+
+func (k *Klondike) Moves() []Move {
+	var moves []Move
+	waste := Pile{Kind: Waste}
+	w, hasWaste := top(k.waste)
+	if hasWaste && toFoundation(w, &k.foundations) {
+		moves = append(moves, Move{From: waste, To: Pile{Foundation, int(w.Suit)}, Count: 1})
+	}
+	for i, pile := range k.tableau {
+		if c, ok := top(pile); ok && toFoundation(c, &k.foundations) {
+			moves = append(moves, Move{From: Pile{Tableau, i}, To: Pile{Foundation, int(c.Suit)}, Count: 1})
+		}
+	}
+	for i, pile := range k.tableau {
+		// every face up run in Klondike is built in sequence, so any part of it can be moved
+		for start := k.faceDown[i]; start < len(pile); start++ {
+			for j := range k.tableau {
+				if j != i && k.toTableau(pile[start], j) {
+					moves = append(moves, Move{From: Pile{Tableau, i}, To: Pile{Tableau, j}, Count: len(pile) - start})
+				}
+			}
+		}
+	}
+	for j := range k.tableau {
+		if hasWaste && k.toTableau(w, j) {
+			moves = append(moves, Move{From: waste, To: Pile{Tableau, j}, Count: 1})
+		}
+	}
+	for s, rank := range k.foundations {
+		if rank == 0 {
+			continue
+		}
+		c := deck.Card{Suit: deck.Suit(s), Rank: deck.Rank(rank)}
+		for j := range k.tableau {
+			if k.toTableau(c, j) {
+				moves = append(moves, Move{From: Pile{Foundation, s}, To: Pile{Tableau, j}, Count: 1})
+			}
+		}
+	}
+	switch {
+	case len(k.stock) > 0:
+		moves = append(moves, Move{From: Pile{Kind: Stock}, To: waste, Count: min(k.draw, len(k.stock))})
+	case len(k.waste) > 0 && (k.passes == 0 || k.pass < k.passes-1):
+		moves = append(moves, Move{From: waste, To: Pile{Kind: Stock}, Count: len(k.waste)})
+	}
+	return moves
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (k *Klondike) Apply(m Move) error {
+	if !contains(k.Moves(), m) {
+		return fmt.Errorf("solitaire: illegal move %s", m)
+	}
+	u := klondikeUndo{move: m}
+	switch {
+	case m.From.Kind == Stock:
+		for i := 0; i < m.Count; i++ {
+			k.waste = append(k.waste, k.stock[len(k.stock)-1])
+			k.stock = k.stock[:len(k.stock)-1]
+		}
+		u.drawn = m.Count
+	case m.To.Kind == Stock:
+		for i := len(k.waste) - 1; i >= 0; i-- {
+			k.stock = append(k.stock, k.waste[i])
+		}
+		k.waste = k.waste[:0]
+		k.pass++
+	default:
+		cards := k.take(m.From, m.Count)
+		k.put(m.To, cards)
+		if i := m.From.Index; m.From.Kind == Tableau && k.faceDown[i] > 0 && len(k.tableau[i]) == k.faceDown[i] {
+			k.faceDown[i]--
+			u.flipped = true
+		}
+	}
+	k.history = append(k.history, u)
+	return nil
+}
+
+// take removes the top count cards of a pile and returns them.
+func (k *Klondike) take(p Pile, count int) []deck.Card {
+	switch p.Kind {
+	case Foundation:
+		c := deck.Card{Suit: deck.Suit(p.Index), Rank: deck.Rank(k.foundations[p.Index])}
+		k.foundations[p.Index]--
+		return []deck.Card{c}
+	case Waste:
+		c := k.waste[len(k.waste)-1]
+		k.waste = k.waste[:len(k.waste)-1]
+		return []deck.Card{c}
+	}
+	pile := k.tableau[p.Index]
+	cards := append([]deck.Card(nil), pile[len(pile)-count:]...)
+	k.tableau[p.Index] = pile[:len(pile)-count]
+	return cards
+}
+
+// put places cards on top of a pile.
+func (k *Klondike) put(p Pile, cards []deck.Card) {
+	switch p.Kind {
+	case Foundation:
+		k.foundations[p.Index] += len(cards)
+	case Waste:
+		k.waste = append(k.waste, cards...)
+	default:
+		k.tableau[p.Index] = append(k.tableau[p.Index], cards...)
+	}
+}
+
+func (k *Klondike) Undo() bool {
+	if len(k.history) == 0 {
+		return false
+	}
+	u := k.history[len(k.history)-1]
+	k.history = k.history[:len(k.history)-1]
+	m := u.move
+	switch {
+	case m.From.Kind == Stock:
+		for i := 0; i < u.drawn; i++ {
+			k.stock = append(k.stock, k.waste[len(k.waste)-1])
+			k.waste = k.waste[:len(k.waste)-1]
+		}
+	case m.To.Kind == Stock:
+		for i := len(k.stock) - 1; i >= 0; i-- {
+			k.waste = append(k.waste, k.stock[i])
+		}
+		k.stock = k.stock[:0]
+		k.pass--
+	default:
+		if u.flipped {
+			k.faceDown[m.From.Index]++
+		}
+		k.put(m.From, k.take(m.To, m.Count))
+	}
+	return true
+}
+
+func (k *Klondike) Won() bool {
+	return k.foundations == [4]int{13, 13, 13, 13}
+}
+
+func (k *Klondike) Hash() uint64 {
+	var h hasher
+	h.cards(k.stock)
+	h.cards(k.waste)
+	for _, rank := range k.foundations {
+		h.b = append(h.b, byte(rank))
+	}
+	if k.passes > 0 {
+		h.b = append(h.b, byte(k.pass))
+	}
+	piles := make([][]byte, len(k.tableau))
+	for i, pile := range k.tableau {
+		var p hasher
+		p.b = append(p.b, byte(k.faceDown[i]))
+		p.cards(pile)
+		piles[i] = p.b
+	}
+	h.piles(piles)
+	return h.sum()
+}
+
+// String draws the game, with face down cards shown as "##".
+func (k *Klondike) String() string {
+	s := fmt.Sprintf("stock %d  waste %s  foundations %v\n", len(k.stock), deck.FormatHand(k.waste), k.foundations)
+	for i, pile := range k.tableau {
+		s += fmt.Sprintf("T%d:", i)
+		for j, c := range pile {
+			if j < k.faceDown[i] {
+				s += " ##"
+			} else {
+				s += " " + deck.FormatHand([]deck.Card{c})
+			}
+		}
+		s += "\n"
+	}
+	return s
+}
+
+// candidates returns the moves worth searching: a safe move to the foundations on its own if there is one,
+// and otherwise every move except those which only shuffle cards around the tableau, i.e. moving part of
+// a face up run unless the card it uncovers can go to the foundations or take the waste's top card,
+// a foundation's top card or a face up card from another pile, or moving a king which is already at the
+// bottom of its pile, or on the foundations, to an empty one.
+func (k *Klondike) candidates() []Move {
+	var moves []Move
+	for _, m := range k.Moves() {
+		if m.To.Kind == Foundation {
+			if c := k.topOf(m.From); safe(c, &k.foundations) {
+				return []Move{m}
+			}
+		}
+		if m.From.Kind == Foundation && len(k.tableau[m.To.Index]) == 0 {
+			continue
+		}
+		if m.From.Kind == Tableau && m.To.Kind == Tableau {
+			i := m.From.Index
+			start := len(k.tableau[i]) - m.Count
+			if start == 0 && len(k.tableau[m.To.Index]) == 0 {
+				continue
+			}
+			if start > k.faceDown[i] && !k.useful(k.tableau[i][start-1], i) {
+				continue
+			}
+		}
+		moves = append(moves, m)
+	}
+	return moves
+}
+
+// useful returns true if uncovering the card in the pile lets something move: the card itself to the
+// foundations, or the waste's top card, a foundation's top card or a face up card from another pile onto it.
+func (k *Klondike) useful(c deck.Card, pile int) bool {
+	if toFoundation(c, &k.foundations) {
+		return true
+	}
+	if w, ok := top(k.waste); ok && stacks(w, c) {
+		return true
+	}
+	for s, rank := range k.foundations {
+		if rank > 0 && stacks(deck.Card{Suit: deck.Suit(s), Rank: deck.Rank(rank)}, c) {
+			return true
+		}
+	}
+	for i, p := range k.tableau {
+		if i == pile {
+			continue
+		}
+		for _, o := range p[k.faceDown[i]:] {
+			if stacks(o, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// topOf returns the top card of a pile.
+func (k *Klondike) topOf(p Pile) deck.Card {
+	switch p.Kind {
+	case Waste:
+		return k.waste[len(k.waste)-1]
+	case Foundation:
+		return deck.Card{Suit: deck.Suit(p.Index), Rank: deck.Rank(k.foundations[p.Index])}
+	}
+	return k.tableau[p.Index][len(k.tableau[p.Index])-1]
+}
+
+// score estimates how far the game is from being won: the cards still to go to the foundations, plus the
+// face down cards, and the face up cards on top of a lower card, which have to be moved out of the way first.
+func (k *Klondike) score() int {
+	n := 52 - k.foundations[0] - k.foundations[1] - k.foundations[2] - k.foundations[3]
+	for i, pile := range k.tableau {
+		n += k.faceDown[i] + blocking(pile[k.faceDown[i]:])
+	}
+	return n
+}
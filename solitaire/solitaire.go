@@ -0,0 +1,125 @@
+// Package solitaire plays Klondike and FreeCell with cards from the deck package, and solves deals.
+package solitaire
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Game is a game of solitaire.
+type Game interface {
+	// Moves returns every legal move.
+	Moves() []Move
+	// Apply makes a move, returning an error if it isn't legal.
+	Apply(m Move) error
+	// Undo takes back the last move, returning false if no moves have been made.
+	Undo() bool
+	// Won returns true once every card is on the foundations.
+	Won() bool
+	// Hash returns a hash of the position, which is the same for positions which only differ in ways
+	// which don't matter, such as the order of the free cells.
+	Hash() uint64
+}
+
+// Kind is a kind of pile.
+type Kind uint8
+
+const (
+	Tableau Kind = iota
+	Foundation
+	Stock // Klondike only
+	Waste // Klondike only
+	Cell  // FreeCell only
+)
+
+var kindShort = [...]string{Tableau: "T", Foundation: "F", Stock: "S", Waste: "W", Cell: "C"}
+
+// Pile identifies a pile. Foundations are numbered by the suit they're built in.
+type Pile struct {
+	Kind  Kind
+	Index int
+}
+
+func (p Pile) String() string {
+	if p.Kind == Stock || p.Kind == Waste {
+		return kindShort[p.Kind]
+	}
+	return fmt.Sprintf("%s%d", kindShort[p.Kind], p.Index)
+}
+
+// Move moves the top Count cards of one pile onto another. In Klondike a move from the stock to the waste
+// turns cards over, and a move from the waste to the stock turns the waste back over to start another pass.
+type Move struct {
+	From, To Pile
+	Count    int
+}
+
+func (m Move) String() string {
+	if m.Count > 1 {
+		return fmt.Sprintf("%s-%s x%d", m.From, m.To, m.Count)
+	}
+	return fmt.Sprintf("%s-%s", m.From, m.To)
+}
+
+func red(c deck.Card) bool {
+	return c.Suit == deck.Diamond || c.Suit == deck.Heart
+}
+
+// stacks returns true if the card can be placed on top in a tableau: one rank lower and the other colour.
+func stacks(card, top deck.Card) bool {
+	return card.Rank+1 == top.Rank && red(card) != red(top)
+}
+
+// safe returns true if a card can go to the foundations without ever being needed in the tableau, because
+// every card which could be placed on it is already on the foundations.
+func safe(c deck.Card, foundations *[4]int) bool {
+	if c.Rank <= deck.Two {
+		return true
+	}
+	for s, top := range foundations {
+		if red(deck.Card{Suit: deck.Suit(s)}) != red(c) && top < int(c.Rank)-1 {
+			return false
+		}
+	}
+	return true
+}
+
+// hasher builds the hash of a position.
+type hasher struct {
+	b []byte
+}
+
+func (h *hasher) cards(cards []deck.Card) {
+	for _, c := range cards {
+		h.b = append(h.b, byte(c.Suit)<<4|byte(c.Rank))
+	}
+	h.b = append(h.b, 0xff)
+}
+
+// piles adds piles whose order doesn't matter.
+func (h *hasher) piles(piles [][]byte) {
+	sort.Slice(piles, func(i, j int) bool { return string(piles[i]) < string(piles[j]) })
+	for _, p := range piles {
+		h.b = append(h.b, p...)
+		h.b = append(h.b, 0xfe)
+	}
+}
+
+func (h *hasher) sum() uint64 {
+	f := fnv.New64a()
+	f.Write(h.b)
+	return f.Sum64()
+}
+
+// contains returns true if the move is one of the moves.
+func contains(moves []Move, m Move) bool {
+	for _, move := range moves {
+		if move == m {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package solitaire
+
+import (
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+func hand(t *testing.T, s string) []deck.Card {
+	cards, err := deck.ParseHand(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+func TestDeal(t *testing.T) {
+	k, err := NewKlondike(deck.New(deck.Seed(1)), KlondikeOptions{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	for i, pile := range k.tableau {
+		if len(pile) != i+1 || k.faceDown[i] != i {
+			t.Errorf("klondike pile %d: want %d cards with %d face down, got %d with %d", i, i+1, i, len(pile), k.faceDown[i])
+		}
+	}
+	if len(k.stock) != 24 {
+		t.Errorf("want 24 cards in the stock, got %d", len(k.stock))
+	}
+	f, err := NewFreeCell(deck.New(deck.Seed(1)))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	for i, pile := range f.tableau {
+		want := 6
+		if i < 4 {
+			want = 7
+		}
+		if len(pile) != want {
+			t.Errorf("freecell pile %d: want %d cards, got %d", i, want, len(pile))
+		}
+	}
+	if _, err := NewKlondike(deck.New(deck.Jokers(2)), KlondikeOptions{}); err == nil {
+		t.Error("klondike: want an error dealing 54 cards, got nil")
+	}
+	if _, err := NewFreeCell(deck.New()[:51]); err == nil {
+		t.Error("freecell: want an error dealing 51 cards, got nil")
+	}
+}
+
+func TestKlondikeMoves(t *testing.T) {
+	k := &Klondike{draw: 3, passes: 2}
+	k.tableau[0] = hand(t, "KS QH JC")
+	k.faceDown[0] = 1
+	k.tableau[1] = hand(t, "5D 4S")
+	k.waste = hand(t, "9C 3H")
+	k.foundations[deck.Spade] = 3
+	tests := []struct {
+		name string
+		move Move
+		want bool
+	}{
+		{"tableau to foundation", Move{From: Pile{Tableau, 1}, To: Pile{Foundation, int(deck.Spade)}, Count: 1}, true},
+		{"waste to tableau", Move{From: Pile{Kind: Waste}, To: Pile{Tableau, 1}, Count: 1}, true},
+		{"face up run to empty pile", Move{From: Pile{Tableau, 0}, To: Pile{Tableau, 2}, Count: 2}, false},
+		{"face down card", Move{From: Pile{Tableau, 0}, To: Pile{Tableau, 2}, Count: 3}, false},
+		{"same colour", Move{From: Pile{Tableau, 1}, To: Pile{Tableau, 0}, Count: 1}, false},
+		{"foundation to tableau", Move{From: Pile{Foundation, int(deck.Spade)}, To: Pile{Tableau, 1}, Count: 1}, false},
+		{"turn back the empty stock", Move{From: Pile{Kind: Waste}, To: Pile{Kind: Stock}, Count: 2}, true},
+	}
+	for _, tt := range tests {
+		if got := contains(k.Moves(), tt.move); got != tt.want {
+			t.Errorf("%s: want %s legal %v, got %v", tt.name, tt.move, tt.want, got)
+		}
+	}
+	if err := k.Apply(Move{From: Pile{Tableau, 0}, To: Pile{Tableau, 2}, Count: 3}); err == nil {
+		t.Error("want an error moving a face down card, got nil")
+	}
+
+	// turning back the waste uses up a pass, and the last pass can't be turned back
+	if err := k.Apply(Move{From: Pile{Kind: Waste}, To: Pile{Kind: Stock}, Count: 2}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if got := deck.FormatHand(k.stock); got != "3H 9C" {
+		t.Errorf("want the stock turned back over as 3H 9C, got %s", got)
+	}
+	if err := k.Apply(Move{From: Pile{Kind: Stock}, To: Pile{Kind: Waste}, Count: 2}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if got := deck.FormatHand(k.waste); got != "9C 3H" {
+		t.Errorf("want the waste to be 9C 3H, got %s", got)
+	}
+	for _, m := range k.Moves() {
+		if m.To.Kind == Stock {
+			t.Errorf("want no passes left, got move %s", m)
+		}
+	}
+}
+
+func TestKlondikeCandidates(t *testing.T) {
+	k := &Klondike{draw: 1}
+	k.tableau[0] = hand(t, "8S 7H 6C")
+	k.tableau[1] = hand(t, "7D")
+	split := Move{From: Pile{Tableau, 0}, To: Pile{Tableau, 1}, Count: 1}
+	if contains(k.candidates(), split) {
+		t.Errorf("want %s left out when nothing can use the 7H, got it", split)
+	}
+	// uncovering the 7H lets the 6S go on it from the waste
+	k.waste = hand(t, "6S")
+	if !contains(k.candidates(), split) {
+		t.Errorf("want %s searched when the waste can use the 7H, got %v", split, k.candidates())
+	}
+}
+
+func TestKlondikeFlip(t *testing.T) {
+	k := &Klondike{draw: 1}
+	k.tableau[0] = hand(t, "2C 9S AH")
+	k.faceDown[0] = 2
+	before := k.Hash()
+	if err := k.Apply(Move{From: Pile{Tableau, 0}, To: Pile{Foundation, int(deck.Heart)}, Count: 1}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if k.faceDown[0] != 1 {
+		t.Errorf("want the 9S turned face up, got %d face down cards", k.faceDown[0])
+	}
+	if !k.Undo() || k.faceDown[0] != 2 || k.Hash() != before {
+		t.Errorf("want undo to turn the 9S back over and restore the position, got %d face down cards", k.faceDown[0])
+	}
+}
+
+func TestFreeCellMoves(t *testing.T) {
+	f := &FreeCell{}
+	f.tableau[0] = hand(t, "KC 8D 7S 6H 5C")
+	f.tableau[1] = hand(t, "9C")
+	f.tableau[2] = hand(t, "KS")
+	for i := 3; i < 8; i++ {
+		f.tableau[i] = hand(t, "AD")
+	}
+	tests := []struct {
+		name  string
+		cells string
+		move  Move
+		want  bool
+	}{
+		{"run through free cells", "", Move{From: Pile{Tableau, 0}, To: Pile{Tableau, 1}, Count: 4}, true},
+		{"run too long for the cells", "2D 3D", Move{From: Pile{Tableau, 0}, To: Pile{Tableau, 1}, Count: 4}, false},
+		{"partial run", "", Move{From: Pile{Tableau, 0}, To: Pile{Tableau, 2}, Count: 1}, false},
+		{"cell to tableau", "8H", Move{From: Pile{Cell, 0}, To: Pile{Tableau, 1}, Count: 1}, true},
+		{"tableau to full cells", "2D 3D 4D 5D", Move{From: Pile{Tableau, 0}, To: Pile{Cell, 0}, Count: 1}, false},
+		{"tableau to foundation", "", Move{From: Pile{Tableau, 3}, To: Pile{Foundation, int(deck.Diamond)}, Count: 1}, true},
+	}
+	for _, tt := range tests {
+		f.cells = [4]deck.Card{}
+		copy(f.cells[:], hand(t, tt.cells))
+		if got := contains(f.Moves(), tt.move); got != tt.want {
+			t.Errorf("%s: want %s legal %v, got %v", tt.name, tt.move, tt.want, got)
+		}
+	}
+}
+
+func TestUndo(t *testing.T) {
+	k, _ := NewKlondike(deck.New(deck.Seed(2)), KlondikeOptions{Draw: 3})
+	f, _ := NewFreeCell(deck.New(deck.Seed(2)))
+	for _, g := range []Game{k, f} {
+		var hashes []uint64
+		for i := 0; i < 40; i++ {
+			moves := g.Moves()
+			if len(moves) == 0 {
+				break
+			}
+			hashes = append(hashes, g.Hash())
+			if err := g.Apply(moves[i%len(moves)]); err != nil {
+				t.Fatalf("%T: unexpected error: %v", g, err)
+			}
+		}
+		for i := len(hashes) - 1; i >= 0; i-- {
+			if !g.Undo() {
+				t.Fatalf("%T: want to undo move %d, got false", g, i)
+			}
+			if g.Hash() != hashes[i] {
+				t.Errorf("%T: want undoing move %d to restore the position", g, i)
+			}
+		}
+		if g.Undo() {
+			t.Errorf("%T: want nothing left to undo, got true", g)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	f, _ := NewFreeCell(deck.New(deck.Seed(3)))
+	g, _ := NewFreeCell(deck.New(deck.Seed(3)))
+	f.Apply(Move{From: Pile{Tableau, 0}, To: Pile{Cell, 0}, Count: 1})
+	g.Apply(Move{From: Pile{Tableau, 0}, To: Pile{Cell, 2}, Count: 1})
+	if f.Hash() != g.Hash() {
+		t.Error("want the same hash for a card in a different free cell")
+	}
+	g.Undo()
+	g.Apply(Move{From: Pile{Tableau, 1}, To: Pile{Cell, 2}, Count: 1})
+	if f.Hash() == g.Hash() {
+		t.Error("want different hashes for a different card in the free cells")
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name string
+		deal func() Game
+	}{
+		{"freecell", func() Game { f, _ := NewFreeCell(deck.New(deck.Seed(1))); return f }},
+		{"klondike", func() Game { k, _ := NewKlondike(deck.New(deck.Seed(1)), KlondikeOptions{}); return k }},
+		{"klondike draw 3", func() Game {
+			k, _ := NewKlondike(deck.New(deck.Seed(4)), KlondikeOptions{Draw: 3})
+			return k
+		}},
+	}
+	for _, tt := range tests {
+		g := tt.deal()
+		before := g.Hash()
+		moves, err := Solve(g, 200000)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if g.Hash() != before || g.Undo() {
+			t.Errorf("%s: want the game left as it was", tt.name)
+		}
+		g = tt.deal()
+		for i, m := range moves {
+			if err := g.Apply(m); err != nil {
+				t.Errorf("%s: move %d: %v", tt.name, i, err)
+				break
+			}
+		}
+		if !g.Won() {
+			t.Errorf("%s: want the %d moves to win", tt.name, len(moves))
+		}
+	}
+}
+
+// stuck is a game with no moves.
+type stuck struct{}
+
+func (stuck) Moves() []Move      { return nil }
+func (stuck) Apply(m Move) error { return nil }
+func (stuck) Undo() bool         { return false }
+func (stuck) Won() bool          { return false }
+func (stuck) Hash() uint64       { return 0 }
+
+func TestSolveFails(t *testing.T) {
+	if _, err := Solve(stuck{}, 0); err != ErrUnsolvable {
+		t.Errorf("no moves: want %v, got %v", ErrUnsolvable, err)
+	}
+	f, _ := NewFreeCell(deck.New(deck.Seed(1)))
+	if _, err := Solve(f, 10); err != ErrLimit {
+		t.Errorf("limit: want %v, got %v", ErrLimit, err)
+	}
+}
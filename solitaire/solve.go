@@ -0,0 +1,153 @@
+package solitaire
+
+import (
+	"container/heap"
+	"errors"
+)
+
+var (
+	// ErrUnsolvable is returned by Solve when it runs out of positions to search without winning. Games
+	// which prune their moves leave some out of the search, so this doesn't prove the deal can't be won.
+	ErrUnsolvable = errors.New("solitaire: no solution")
+	// ErrLimit is returned by Solve when it gives up after searching the maximum number of positions.
+	ErrLimit = errors.New("solitaire: search limit reached")
+)
+
+// pruner is implemented by games which can leave moves which rarely help out of the search, at the cost of
+// occasionally missing a solution.
+type pruner interface {
+	candidates() []Move
+}
+
+// scorer is implemented by games which can estimate how far a position is from being won; lower is better.
+type scorer interface {
+	score() int
+}
+
+// Solve searches for a way to win the game from its current position, and returns the moves to play. It
+// searches the most promising positions first, skipping positions it has already seen, and gives up with
+// ErrLimit after seeing maxStates positions, or doesn't give up if maxStates is 0. The game is left as it
+// was.
+//
+// The solver sees every card, including Klondike's face down cards and stock, so a deal it solves might
+// not be won by a player who has to guess.
+func Solve(g Game, maxStates int) ([]Move, error) {
+	s := solver{g: g, seen: map[uint64]bool{g.Hash(): true}}
+	root := &node{}
+	s.cur = root
+	defer s.walk(root)
+	if g.Won() {
+		return []Move{}, nil
+	}
+	heap.Push(&s.queue, root)
+	for s.queue.Len() > 0 {
+		n := heap.Pop(&s.queue).(*node)
+		s.walk(n)
+		for _, m := range s.moves() {
+			if err := g.Apply(m); err != nil {
+				continue
+			}
+			h := g.Hash()
+			if s.seen[h] {
+				g.Undo()
+				continue
+			}
+			child := &node{parent: n, move: m, depth: n.depth + 1, seq: len(s.seen)}
+			if g.Won() {
+				g.Undo()
+				return child.path(), nil
+			}
+			if maxStates > 0 && len(s.seen) >= maxStates {
+				g.Undo()
+				return nil, ErrLimit
+			}
+			s.seen[h] = true
+			child.priority = s.priority(child)
+			g.Undo()
+			heap.Push(&s.queue, child)
+		}
+	}
+	return nil, ErrUnsolvable
+}
+
+type solver struct {
+	g     Game
+	seen  map[uint64]bool
+	queue queue
+	cur   *node // the position the game is in
+}
+
+func (s *solver) moves() []Move {
+	if p, ok := s.g.(pruner); ok {
+		return p.candidates()
+	}
+	return s.g.Moves()
+}
+
+// priority returns the priority of the position the game is in. Without a score the search is breadth first.
+func (s *solver) priority(n *node) int {
+	if sc, ok := s.g.(scorer); ok {
+		return sc.score()
+	}
+	return n.depth
+}
+
+// walk puts the game in the position of the node, by undoing moves back to the position it shares with
+// the current node and then making the moves which lead to the node.
+func (s *solver) walk(n *node) {
+	var moves []Move
+	cur, target := s.cur, n
+	for cur.depth > n.depth {
+		s.g.Undo()
+		cur = cur.parent
+	}
+	for n.depth > cur.depth {
+		moves = append(moves, n.move)
+		n = n.parent
+	}
+	for cur != n {
+		s.g.Undo()
+		cur = cur.parent
+		moves = append(moves, n.move)
+		n = n.parent
+	}
+	for i := len(moves) - 1; i >= 0; i-- {
+		s.g.Apply(moves[i])
+	}
+	s.cur = target
+}
+
+// node is a position in the search, reached by making a move from its parent.
+type node struct {
+	parent   *node
+	move     Move
+	depth    int
+	priority int
+	seq      int // the order positions were seen in, which breaks ties
+}
+
+func (n *node) path() []Move {
+	moves := make([]Move, n.depth)
+	for ; n.parent != nil; n = n.parent {
+		moves[n.depth-1] = n.move
+	}
+	return moves
+}
+
+type queue []*node
+
+func (q queue) Len() int { return len(q) }
+func (q queue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(*node)) }
+func (q *queue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
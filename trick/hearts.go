@@ -0,0 +1,146 @@
+package trick
+
+import (
+	"fmt"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+var (
+	twoOfClubs    = deck.Card{Suit: deck.Club, Rank: deck.Two}
+	queenOfSpades = deck.Card{Suit: deck.Spade, Rank: deck.Queen}
+)
+
+// heartsPoints returns the points a card is worth to the player who takes it in Hearts.
+func heartsPoints(c deck.Card) int {
+	switch {
+	case c.Suit == deck.Heart:
+		return 1
+	case c == queenOfSpades:
+		return 13
+	}
+	return 0
+}
+
+// moon is the number of points in a deck; a player who takes them all has shot the moon.
+const moon = 26
+
+// hearts are the rules of Hearts: three cards are passed to the left, right, across and then not at all,
+// the two of clubs leads, no points can be played to the first trick, and hearts can't be led until
+// they're broken. Each heart is a point and the queen of spades thirteen, unless one player takes them
+// all, when everyone else scores 26. The lowest score when someone reaches the target wins.
+type hearts struct{}
+
+func (hearts) less(cards []deck.Card) func(i, j int) bool {
+	return deck.By(deck.SuitOrder(deck.Club, deck.Diamond, deck.Spade, deck.Heart), deck.AceHigh)(cards)
+}
+
+// passOffsets are the seats, relative to the passer, passed to on each hand in turn; 0 holds.
+var passOffsets = [...]int{1, 3, 2, 0}
+
+func (hearts) begin(g *Game) (int, error) {
+	if offset := passOffsets[(g.hand-1)%len(passOffsets)]; offset != 0 {
+		var passed [Seats][]deck.Card
+		for seat, p := range g.players {
+			to := (seat + offset) % Seats
+			cards := p.Pass(copyCards(g.hands[seat]), to)
+			if err := checkPass(g.hands[seat], cards); err != nil {
+				return 0, fmt.Errorf("seat %d: %w", seat, err)
+			}
+			passed[to] = cards
+		}
+		for seat := range g.hands {
+			from := (seat - offset + Seats) % Seats
+			for _, c := range passed[seat] {
+				g.hands[from] = remove(g.hands[from], c)
+			}
+		}
+		for seat := range g.hands {
+			g.hands[seat] = deck.Sort(g.rules.less)(append(g.hands[seat], passed[seat]...))
+		}
+	}
+	for seat, hand := range g.hands {
+		if contains(hand, twoOfClubs) {
+			return seat, nil
+		}
+	}
+	return 0, nil
+}
+
+// checkPass returns an error unless the cards are three different cards from the hand.
+func checkPass(hand, cards []deck.Card) error {
+	if len(cards) != 3 {
+		return fmt.Errorf("passed %d cards instead of 3", len(cards))
+	}
+	left := copyCards(hand)
+	for _, c := range cards {
+		if !contains(left, c) {
+			return fmt.Errorf("passed %s, which isn't in the hand", c)
+		}
+		left = remove(left, c)
+	}
+	return nil
+}
+
+func (hearts) legal(g *Game, seat int) []deck.Card {
+	hand, t := g.hands[seat], g.trick
+	switch {
+	case t.Number == 1 && len(t.Cards) == 0:
+		return []deck.Card{twoOfClubs}
+	case len(t.Cards) == 0:
+		if g.broken {
+			return copyCards(hand)
+		}
+		return prefer(hand, func(c deck.Card) bool { return c.Suit != deck.Heart })
+	}
+	cards := follow(hand, t)
+	if t.Number == 1 && cards[0].Suit != t.Suit() {
+		return prefer(cards, func(c deck.Card) bool { return heartsPoints(c) == 0 })
+	}
+	return cards
+}
+
+// prefer returns the cards which pass keep, or all of them if none do.
+func prefer(cards []deck.Card, keep func(deck.Card) bool) []deck.Card {
+	if kept := filter(cards, keep); len(kept) > 0 {
+		return kept
+	}
+	return copyCards(cards)
+}
+
+func (hearts) breaks() deck.Suit {
+	return deck.Heart
+}
+
+func (hearts) winner(t Trick) int {
+	return highest(t, t.Suit())
+}
+
+func (hearts) score(g *Game) [Seats]int {
+	points := g.points
+	for seat, p := range points {
+		if p == moon {
+			for other := range points {
+				points[other] = moon
+			}
+			points[seat] = 0
+			break
+		}
+	}
+	for seat, p := range points {
+		g.score[seat] += p
+	}
+	return points
+}
+
+func (hearts) winners(g *Game, over bool) []int {
+	for _, s := range g.score {
+		if s >= g.target {
+			over = true
+		}
+	}
+	if !over {
+		return nil
+	}
+	return best(g.score, false)
+}
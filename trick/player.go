@@ -0,0 +1,54 @@
+package trick
+
+import (
+	"sort"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Simple is a Player with simple habits: it passes its highest cards, bids its aces, kings and long
+// spades, plays its lowest card when following suit and throws away its highest when it can't.
+type Simple struct{}
+
+func (Simple) Pass(hand []deck.Card, to int) []deck.Card {
+	sort.Slice(hand, func(i, j int) bool { return deck.AceHigh(hand[i], hand[j]) > 0 })
+	return hand[:3]
+}
+
+func (Simple) Bid(hand []deck.Card, bids []int) int {
+	bid, spades := 0, 0
+	for _, c := range hand {
+		switch {
+		case c.Rank == deck.Ace, c.Rank == deck.King && c.Suit != deck.Spade:
+			bid++
+		}
+		if c.Suit == deck.Spade {
+			spades++
+		}
+	}
+	if spades > 3 {
+		bid += spades - 3
+	}
+	if bid == 0 {
+		bid = 1 // never bid nil
+	}
+	return bid
+}
+
+func (Simple) Play(hand []deck.Card, trick Trick, legal []deck.Card) deck.Card {
+	sort.Slice(legal, func(i, j int) bool { return deck.AceHigh(legal[i], legal[j]) < 0 })
+	if len(trick.Cards) > 0 && legal[0].Suit != trick.Suit() {
+		// can't follow suit: the queen of spades is best given away, otherwise the highest card
+		for _, c := range legal {
+			if c == queenOfSpades {
+				return c
+			}
+		}
+		return legal[len(legal)-1]
+	}
+	return legal[0]
+}
+
+func (Simple) Results(r HandResult) {
+	// noop
+}
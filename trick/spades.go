@@ -0,0 +1,104 @@
+package trick
+
+import (
+	"fmt"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// spades are the rules of partnership Spades: seats 0 and 2 play against seats 1 and 3, spades are always
+// trumps and can't be led until they're broken, and each player bids the tricks they expect to take, or
+// nil for none. A partnership which takes at least the sum of its bids scores ten per trick bid and one
+// per overtrick, or bag, otherwise it loses ten per trick bid. A nil bid scores 100 if the player takes no
+// tricks and loses 100 if they take any, which count as bags. Every ten bags cost 100. The best score when
+// a partnership reaches the target wins.
+type spades struct{}
+
+func (spades) less(cards []deck.Card) func(i, j int) bool {
+	return deck.By(deck.BridgeSuits, deck.AceHigh)(cards)
+}
+
+func (spades) begin(g *Game) (int, error) {
+	dealer := (g.hand - 1) % Seats
+	var bids []int
+	for i := 1; i <= Seats; i++ {
+		seat := (dealer + i) % Seats
+		bid := g.players[seat].Bid(copyCards(g.hands[seat]), append([]int(nil), bids...))
+		if bid < 0 || bid > 13 {
+			return 0, fmt.Errorf("seat %d bid %d, which isn't between 0 and 13", seat, bid)
+		}
+		bids = append(bids, bid)
+		g.bids[seat] = bid
+	}
+	return (dealer + 1) % Seats, nil
+}
+
+func (spades) legal(g *Game, seat int) []deck.Card {
+	hand, t := g.hands[seat], g.trick
+	if len(t.Cards) == 0 && !g.broken {
+		return prefer(hand, func(c deck.Card) bool { return c.Suit != deck.Spade })
+	}
+	return follow(hand, t)
+}
+
+func (spades) breaks() deck.Suit {
+	return deck.Spade
+}
+
+func (spades) winner(t Trick) int {
+	if i := highest(t, deck.Spade); i >= 0 {
+		return i
+	}
+	return highest(t, t.Suit())
+}
+
+func (spades) score(g *Game) [Seats]int {
+	var points [Seats]int
+	for team := 0; team < Seats/2; team++ {
+		contract, tricks, bags, p := 0, 0, 0, 0
+		for _, seat := range []int{team, team + 2} {
+			if g.bids[seat] > 0 {
+				contract += g.bids[seat]
+				tricks += g.tricks[seat]
+				continue
+			}
+			if g.tricks[seat] == 0 {
+				p += 100
+			} else {
+				p -= 100
+				bags += g.tricks[seat]
+			}
+		}
+		if contract > 0 {
+			if tricks >= contract {
+				p += 10*contract + tricks - contract
+				bags += tricks - contract
+			} else {
+				p -= 10 * contract
+			}
+		}
+		g.bags[team] += bags
+		for g.bags[team] >= 10 {
+			g.bags[team] -= 10
+			p -= 100
+		}
+		g.bags[team+2] = g.bags[team]
+		for _, seat := range []int{team, team + 2} {
+			points[seat] = p
+			g.score[seat] += p
+		}
+	}
+	return points
+}
+
+func (spades) winners(g *Game, over bool) []int {
+	for _, s := range g.score {
+		if s >= g.target {
+			over = true
+		}
+	}
+	if !over {
+		return nil
+	}
+	return best(g.score, true)
+}
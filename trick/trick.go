@@ -0,0 +1,301 @@
+// Package trick plays trick-taking card games, Hearts and Spades, between four seats whose cards are
+// chosen by a Player.
+package trick
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// Seats is the number of players at the table. Seats are numbered clockwise, so the seat on a player's
+// left is the next one.
+const Seats = 4
+
+// Player chooses the cards for a seat, like blackjack.AI does for a blackjack seat. The methods which
+// only apply to the other game are never called, e.g. Bid when playing Hearts.
+type Player interface {
+	// Pass returns three cards from the hand to pass to the seat given at the start of a hand of Hearts.
+	Pass(hand []deck.Card, to int) []deck.Card
+	// Bid returns the number of tricks the player expects to take in a hand of Spades, with 0 bidding
+	// nil. Bids holds the bids made so far, starting with the first bidder.
+	Bid(hand []deck.Card, bids []int) int
+	// Play returns the card to play to the trick, which must be one of the legal cards.
+	Play(hand []deck.Card, trick Trick, legal []deck.Card) deck.Card
+	// Results is told the result of every hand.
+	Results(r HandResult)
+}
+
+// Variant is the game being played.
+type Variant uint8
+
+const (
+	Hearts Variant = iota
+	Spades
+)
+
+func (v Variant) String() string {
+	switch v {
+	case Hearts:
+		return "Hearts"
+	case Spades:
+		return "Spades"
+	}
+	return fmt.Sprintf("Variant(%d)", v)
+}
+
+type Options struct {
+	Variant Variant
+	Target  int   // the score which ends the game; defaults to 100 for Hearts and 500 for Spades
+	Seed    int64 // seed for shuffling; if 0, a seed is chosen from the current time
+	// MaxHands ends the game after this many hands even if no one has reached the target; 0 means
+	// there is no limit.
+	MaxHands int
+}
+
+// Trick is a trick being played: the seat which led it and the cards played so far, in the order they
+// were played.
+type Trick struct {
+	Number int // the trick's number in the hand, from 1
+	Leader int
+	Cards  []deck.Card
+}
+
+// Seat returns the seat which played the trick's i'th card.
+func (t Trick) Seat(i int) int {
+	return (t.Leader + i) % Seats
+}
+
+// Suit returns the suit which was led. It's only meaningful once a card has been played.
+func (t Trick) Suit() deck.Suit {
+	return t.Cards[0].Suit
+}
+
+// HandResult is the result of a hand. Score holds each seat's total after the hand; in Spades a
+// partnership (seats 0 and 2, and seats 1 and 3) shares its score.
+type HandResult struct {
+	Hand   int // the hand's number, from 1
+	Bids   [Seats]int
+	Tricks [Seats]int
+	Points [Seats]int // the points each seat scored in the hand
+	Score  [Seats]int
+}
+
+// Result is the result of a game.
+type Result struct {
+	Hands   int
+	Score   [Seats]int
+	Winners []int // every seat with the best score; in Spades both partners win
+}
+
+// Game is a game of Hearts or Spades.
+type Game struct {
+	variant  Variant
+	target   int
+	seed     int64
+	maxHands int
+	rules    rules
+
+	players [Seats]Player
+	hand    int // the number of the hand being played
+	hands   [Seats][]deck.Card
+	trick   Trick
+	broken  bool // hearts (in Hearts) or spades (in Spades) have been played, so they can be led
+	bids    [Seats]int
+	tricks  [Seats]int
+	points  [Seats]int // the points taken in tricks during the hand, in Hearts
+	score   [Seats]int
+	bags    [Seats]int // overtricks taken by each partnership in Spades, counted at both partners' seats
+}
+
+func New(opts Options) Game {
+	g := Game{
+		variant:  opts.Variant,
+		target:   opts.Target,
+		seed:     opts.Seed,
+		maxHands: opts.MaxHands,
+	}
+	switch opts.Variant {
+	case Spades:
+		g.rules = spades{}
+		if g.target == 0 {
+			g.target = 500
+		}
+	default:
+		g.rules = hearts{}
+		if g.target == 0 {
+			g.target = 100
+		}
+	}
+	return g
+}
+
+// rules are the parts of a game which differ between Hearts and Spades.
+type rules interface {
+	// less orders a hand for showing to a player.
+	less(cards []deck.Card) func(i, j int) bool
+	// begin starts a hand, after the cards are dealt, and returns the seat which leads.
+	begin(g *Game) (int, error)
+	// legal returns the cards the seat may play to the current trick.
+	legal(g *Game, seat int) []deck.Card
+	// breaks returns the suit which can't be led until it's been played on another suit's trick.
+	breaks() deck.Suit
+	// winner returns the position in the trick of the card which takes it.
+	winner(t Trick) int
+	// score adds the points for the hand to each seat's score and returns them.
+	score(g *Game) [Seats]int
+	// winners returns the seats which have won, or nil if the game isn't over. When over is true the
+	// game has run out of hands, so the seats with the best score win.
+	winners(g *Game, over bool) []int
+}
+
+// Play plays hands until the game is over, with players[i] playing seat i. If a player makes an illegal
+// play, the game stops and returns the score so far along with the error.
+func (g *Game) Play(players [Seats]Player) (Result, error) {
+	for _, p := range players {
+		if p == nil {
+			return Result{}, errors.New("trick: every seat needs a player")
+		}
+	}
+	if g.seed == 0 {
+		g.seed = time.Now().UnixNano()
+	}
+	g.players = players
+	shuffle := deck.Seed(g.seed)
+	var winners []int
+	for g.hand = 1; winners == nil && (g.maxHands == 0 || g.hand <= g.maxHands); g.hand++ {
+		if err := playHand(g, deck.New(shuffle)); err != nil {
+			return Result{Hands: g.hand - 1, Score: g.score}, fmt.Errorf("hand %d: %w", g.hand, err)
+		}
+		winners = g.rules.winners(g, false)
+	}
+	if winners == nil {
+		winners = g.rules.winners(g, true)
+	}
+	return Result{Hands: g.hand - 1, Score: g.score, Winners: winners}, nil
+}
+
+// playHand deals the cards and plays all thirteen tricks.
+func playHand(g *Game, cards []deck.Card) error {
+	for i := range g.hands {
+		g.hands[i] = deck.Sort(g.rules.less)(append([]deck.Card(nil), cards[i*13:(i+1)*13]...))
+	}
+	g.broken = false
+	g.bids = [Seats]int{}
+	g.tricks = [Seats]int{}
+	g.points = [Seats]int{}
+	leader, err := g.rules.begin(g)
+	if err != nil {
+		return err
+	}
+	for n := 1; n <= 13; n++ {
+		g.trick = Trick{Number: n, Leader: leader, Cards: make([]deck.Card, 0, Seats)}
+		for i := 0; i < Seats; i++ {
+			seat := g.trick.Seat(i)
+			legal := g.rules.legal(g, seat)
+			card := g.players[seat].Play(copyCards(g.hands[seat]), copyTrick(g.trick), copyCards(legal))
+			if !contains(g.hands[seat], card) {
+				return fmt.Errorf("seat %d played %s, which isn't in their hand", seat, card)
+			}
+			if !contains(legal, card) {
+				return fmt.Errorf("seat %d played %s, which isn't legal", seat, card)
+			}
+			g.hands[seat] = remove(g.hands[seat], card)
+			g.trick.Cards = append(g.trick.Cards, card)
+			if card.Suit == g.rules.breaks() {
+				g.broken = true
+			}
+		}
+		leader = g.trick.Seat(g.rules.winner(g.trick))
+		g.tricks[leader]++
+		for _, c := range g.trick.Cards {
+			g.points[leader] += heartsPoints(c)
+		}
+	}
+	r := HandResult{Hand: g.hand, Bids: g.bids, Tricks: g.tricks, Points: g.rules.score(g), Score: g.score}
+	for _, p := range g.players {
+		p.Results(r)
+	}
+	return nil
+}
+
+// best returns the seats with the lowest score, or the highest if high is true.
+func best(score [Seats]int, high bool) []int {
+	b := score[0]
+	for _, s := range score[1:] {
+		if (high && s > b) || (!high && s < b) {
+			b = s
+		}
+	}
+	var seats []int
+	for seat, s := range score {
+		if s == b {
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
+
+// highest returns the position in the trick of the highest card of the suit, aces high, or -1 if none of
+// the cards are of the suit.
+func highest(t Trick, suit deck.Suit) int {
+	best := -1
+	for i, c := range t.Cards {
+		if c.Suit == suit && (best < 0 || deck.AceHigh(c, t.Cards[best]) > 0) {
+			best = i
+		}
+	}
+	return best
+}
+
+// follow returns the cards which can be played to the trick when following suit: the cards of the suit
+// led if there are any, otherwise the whole hand.
+func follow(hand []deck.Card, t Trick) []deck.Card {
+	if len(t.Cards) == 0 {
+		return copyCards(hand)
+	}
+	if suited := filter(hand, func(c deck.Card) bool { return c.Suit == t.Suit() }); len(suited) > 0 {
+		return suited
+	}
+	return copyCards(hand)
+}
+
+func filter(cards []deck.Card, keep func(deck.Card) bool) []deck.Card {
+	var ret []deck.Card
+	for _, c := range cards {
+		if keep(c) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func contains(cards []deck.Card, card deck.Card) bool {
+	for _, c := range cards {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
+
+// remove returns the cards without the first copy of card.
+func remove(cards []deck.Card, card deck.Card) []deck.Card {
+	for i, c := range cards {
+		if c == card {
+			return append(cards[:i:i], cards[i+1:]...)
+		}
+	}
+	return cards
+}
+
+func copyCards(cards []deck.Card) []deck.Card {
+	return append([]deck.Card(nil), cards...)
+}
+
+func copyTrick(t Trick) Trick {
+	t.Cards = copyCards(t.Cards)
+	return t
+}
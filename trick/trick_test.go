@@ -0,0 +1,210 @@
+package trick
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeremy-miller/gophercises/deck"
+)
+
+// recorder is a Simple player which keeps every hand's result.
+type recorder struct {
+	Simple
+	results []HandResult
+}
+
+func (r *recorder) Results(hr HandResult) {
+	r.results = append(r.results, hr)
+}
+
+func players() ([Seats]Player, *recorder) {
+	r := &recorder{}
+	return [Seats]Player{r, Simple{}, Simple{}, Simple{}}, r
+}
+
+func TestHearts(t *testing.T) {
+	g := New(Options{Seed: 1})
+	ps, r := players()
+	result, err := g.Play(ps)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(r.results) != result.Hands {
+		t.Errorf("want results for %d hands, got %d", result.Hands, len(r.results))
+	}
+	for _, hr := range r.results {
+		sum, tricks := 0, 0
+		for seat := range hr.Points {
+			sum += hr.Points[seat]
+			tricks += hr.Tricks[seat]
+		}
+		if (sum != moon && sum != 3*moon) || tricks != 13 {
+			t.Errorf("hand %d: want 26 points (or 78 for the moon) in 13 tricks, got %d in %d", hr.Hand, sum, tricks)
+		}
+	}
+	over := false
+	for seat, s := range result.Score {
+		over = over || s >= 100
+		for _, w := range result.Winners {
+			if s < result.Score[w] {
+				t.Errorf("seat %d scored %d, less than winner %d's %d", seat, s, w, result.Score[w])
+			}
+		}
+	}
+	if !over || len(result.Winners) == 0 {
+		t.Errorf("want the game to end with a seat on 100 or more and a winner, got %+v", result)
+	}
+}
+
+func TestSpades(t *testing.T) {
+	g := New(Options{Variant: Spades, Seed: 2, MaxHands: 40})
+	ps, r := players()
+	result, err := g.Play(ps)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	for _, hr := range r.results {
+		if hr.Score[0] != hr.Score[2] || hr.Score[1] != hr.Score[3] {
+			t.Errorf("hand %d: want partners to share a score, got %v", hr.Hand, hr.Score)
+		}
+	}
+	if len(result.Winners) != 2 || result.Winners[1] != result.Winners[0]+2 {
+		t.Errorf("want a partnership to win, got %v", result.Winners)
+	}
+}
+
+// hand parses cards for a test, failing it if they don't parse.
+func hand(t *testing.T, s string) []deck.Card {
+	cards, err := deck.ParseHand(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+func TestLegal(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		hand    string
+		trick   string
+		number  int
+		broken  bool
+		want    string
+	}{
+		{"hearts opening lead", Hearts, "2C 5C AH", "", 1, false, "2C"},
+		{"follow suit", Hearts, "3C 5C AH QS", "2C", 1, false, "3C 5C"},
+		{"no points on the first trick", Hearts, "AH QS 4D", "2C", 1, false, "4D"},
+		{"points on the first trick when there's nothing else", Hearts, "AH QS", "2C", 1, false, "AH QS"},
+		{"hearts not broken", Hearts, "AH 3H 4D", "", 2, false, "4D"},
+		{"hearts broken", Hearts, "AH 4D", "", 2, true, "AH 4D"},
+		{"only hearts", Hearts, "AH 3H", "", 2, false, "AH 3H"},
+		{"spades not broken", Spades, "AS 4D", "", 1, false, "4D"},
+		{"trump when void", Spades, "AS 4D", "2H", 1, false, "AS 4D"},
+	}
+	for _, tt := range tests {
+		g := New(Options{Variant: tt.variant})
+		g.hands[0] = hand(t, tt.hand)
+		g.trick = Trick{Number: tt.number, Leader: 3, Cards: hand(t, tt.trick)}
+		if len(g.trick.Cards) == 0 {
+			g.trick.Leader = 0
+		}
+		g.broken = tt.broken
+		if got := deck.FormatHand(g.rules.legal(&g, 0)); got != tt.want {
+			t.Errorf("%s: want %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWinner(t *testing.T) {
+	tests := []struct {
+		variant Variant
+		trick   string
+		want    int
+	}{
+		{Hearts, "5C KC AH 2C", 1},
+		{Hearts, "5C 3C 2C 4C", 0},
+		{Spades, "5C KC 2S 3S", 3},
+		{Spades, "AD KD QD 9H", 0},
+	}
+	for _, tt := range tests {
+		g := New(Options{Variant: tt.variant})
+		if got := g.rules.winner(Trick{Cards: hand(t, tt.trick)}); got != tt.want {
+			t.Errorf("%s %s: want card %d to win, got %d", tt.variant, tt.trick, tt.want, got)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	g := New(Options{})
+	g.points = [Seats]int{0, moon, 0, 0}
+	if got := g.rules.score(&g); got != [Seats]int{26, 0, 26, 26} {
+		t.Errorf("shooting the moon: want everyone else to score 26, got %v", got)
+	}
+
+	tests := []struct {
+		name   string
+		bids   [Seats]int
+		tricks [Seats]int
+		bags   int
+		want   [Seats]int
+	}{
+		{"made with bags", [Seats]int{3, 2, 2, 2}, [Seats]int{4, 2, 3, 4}, 0, [Seats]int{52, 42, 52, 42}},
+		{"set", [Seats]int{5, 3, 4, 1}, [Seats]int{4, 2, 4, 3}, 0, [Seats]int{-90, 41, -90, 41}},
+		{"nil made and failed", [Seats]int{0, 0, 4, 4}, [Seats]int{0, 1, 6, 6}, 0, [Seats]int{142, -58, 142, -58}},
+		{"ten bags", [Seats]int{2, 4, 2, 4}, [Seats]int{3, 4, 2, 4}, 9, [Seats]int{-59, 80, -59, 80}},
+	}
+	for _, tt := range tests {
+		g := New(Options{Variant: Spades})
+		g.bids, g.tricks = tt.bids, tt.tricks
+		g.bags = [Seats]int{tt.bags, 0, tt.bags, 0}
+		if got := g.rules.score(&g); got != tt.want {
+			t.Errorf("%s: want %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// cheat plays a card it doesn't have.
+type cheat struct {
+	Simple
+}
+
+func (cheat) Play(hand []deck.Card, trick Trick, legal []deck.Card) deck.Card {
+	return deck.Card{Suit: deck.Joker}
+}
+
+// rewriter edits the legal cards to allow one it doesn't have.
+type rewriter struct {
+	Simple
+}
+
+func (rewriter) Play(hand []deck.Card, trick Trick, legal []deck.Card) deck.Card {
+	legal[0] = deck.Card{Suit: deck.Joker}
+	return legal[0]
+}
+
+// badPasser passes only one card.
+type badPasser struct {
+	Simple
+}
+
+func (badPasser) Pass(hand []deck.Card, to int) []deck.Card {
+	return hand[:1]
+}
+
+func TestIllegal(t *testing.T) {
+	for _, p := range []Player{cheat{}, rewriter{}, badPasser{}} {
+		g := New(Options{Seed: 3})
+		result, err := g.Play([Seats]Player{Simple{}, p, Simple{}, Simple{}})
+		if err == nil || !strings.Contains(err.Error(), "seat 1") {
+			t.Errorf("%T: want an error for seat 1, got %v", p, err)
+		}
+		if result.Hands != 0 {
+			t.Errorf("%T: want no hands finished, got %d", p, result.Hands)
+		}
+	}
+	g := New(Options{})
+	if _, err := g.Play([Seats]Player{Simple{}}); err == nil {
+		t.Error("want an error for empty seats, got nil")
+	}
+}